@@ -0,0 +1,174 @@
+package main
+
+import "fmt"
+
+// Align is where narrower art goes in the space it's given
+type Align int
+
+const (
+	AlignDefault Align = iota // left, or right for right-to-left lines
+	AlignLeft
+	AlignCenter
+	AlignRight
+	AlignJustify // wider spaces between words, so the line fills the width
+)
+
+// alignNames are the names used by --align
+var alignNames = map[string]Align{
+	"left":    AlignLeft,
+	"center":  AlignCenter,
+	"right":   AlignRight,
+	"justify": AlignJustify,
+}
+
+// ParseAlign reads an --align value
+func ParseAlign(value string) (Align, error) {
+	align, ok := alignNames[value]
+	if !ok {
+		return AlignDefault, fmt.Errorf("unknown alignment %q (expected left, center, right or justify)", value)
+	}
+	return align, nil
+}
+
+// alignCells pads a row of cells with spaces to width, on the side(s) the
+// alignment says; the padding is never colored
+func alignCells(row []cell, width int, align Align) []cell {
+	extra := width - len(row)
+	if extra <= 0 {
+		return row
+	}
+	before := 0
+	switch align {
+	case AlignCenter:
+		before = extra / 2
+	case AlignRight:
+		before = extra
+	}
+	out := append(blankCells(before), row...)
+	return append(out, blankCells(extra-before)...)
+}
+
+// renderedLine is one line of output text (or one piece of a wrapped line)
+// and what's needed to line it up: the text and the color positions are
+// kept so the line can be drawn again with wider spaces for justify
+type renderedLine struct {
+	rows    grid
+	text    string
+	indexes []int
+	rtl     bool
+	justify bool // false for the last piece of a wrapped line
+}
+
+// gridWidth is the width of the widest row of a grid
+func gridWidth(g grid) int {
+	width := 0
+	for _, row := range g {
+		width = max(width, len(row))
+	}
+	return width
+}
+
+// alignLines puts the rendered lines under each other, each one lined up
+// within opts.Width (or the widest line when there is no width) as
+// opts.Align says; blank lines stay empty
+func alignLines(lines []renderedLine, banner Banner, colorCode string, opts RenderOptions) grid {
+	width := opts.Width
+	if width == 0 {
+		for _, line := range lines {
+			width = max(width, gridWidth(line.rows))
+		}
+	}
+
+	var rows grid
+	for _, line := range lines {
+		align := opts.Align
+		if align == AlignDefault && line.rtl {
+			align = AlignRight
+		}
+		if align == AlignJustify {
+			if line.justify {
+				line.rows = justifyLine(line, banner, colorCode, opts, width-gridWidth(line.rows))
+			}
+			// what justify can't fill goes where the line starts
+			align = AlignLeft
+			if line.rtl {
+				align = AlignRight
+			}
+		}
+		if line.text == "" || align == AlignDefault || align == AlignLeft {
+			rows = append(rows, line.rows...)
+			continue
+		}
+		for _, row := range line.rows.padded() {
+			rows = append(rows, alignCells(row, width, align))
+		}
+	}
+	return rows
+}
+
+// justifyLine draws a line again with extra blank columns shared out
+// between the gaps between its words (the first gaps get one more if it
+// doesn't share out evenly); a line without gaps is returned as it was
+func justifyLine(line renderedLine, banner Banner, colorCode string, opts RenderOptions, extra int) grid {
+	chars := []rune(line.text)
+
+	// a gap is a run of spaces with a word on both sides; the blank
+	// columns go after the gap's last space
+	var gaps []int
+	for i := 1; i < len(chars)-1; i++ {
+		if chars[i] == ' ' && chars[i+1] != ' ' && hasWordBefore(chars, i) {
+			gaps = append(gaps, i)
+		}
+	}
+	if extra <= 0 || len(gaps) == 0 {
+		return line.rows
+	}
+
+	g := renderLineGrid(chars, banner, colorCode, line.indexes, opts)
+	for r, row := range g {
+		// right to left through the gaps, so earlier columns don't move
+		for j := len(gaps) - 1; j >= 0; j-- {
+			share := extra / len(gaps)
+			if j < extra%len(gaps) {
+				share++
+			}
+			at := cellsEnd(row, gaps[j])
+			if at < 0 && r > 0 {
+				at = min(cellsEnd(g[0], gaps[j]), len(row))
+			}
+			if at < 0 {
+				continue
+			}
+			row = append(row[:at], append(blankCells(share), row[at:]...)...)
+		}
+		g[r] = row
+	}
+
+	// the same effects as the line was drawn with
+	g = opts.distort(g)
+	if line.rtl {
+		g = g.padded()
+	}
+	return g
+}
+
+// hasWordBefore reports whether there is a non-space before position i
+func hasWordBefore(chars []rune, i int) bool {
+	for j := i - 1; j >= 0; j-- {
+		if chars[j] != ' ' {
+			return true
+		}
+	}
+	return false
+}
+
+// cellsEnd returns the position just after the last cell drawn by the
+// character at position owner, or -1 if it drew nothing in this row
+func cellsEnd(row []cell, owner int) int {
+	for i := len(row) - 1; i >= 0; i-- {
+		if row[i].owner == owner {
+			return i + 1
+		}
+	}
+	return -1
+}
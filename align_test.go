@@ -0,0 +1,62 @@
+package main
+
+import "testing"
+
+// test left, center and right inside a width
+func TestRenderAlign(t *testing.T) {
+	tests := []struct {
+		align Align
+		want  string
+	}{
+		{AlignLeft, "ab\n"},
+		{AlignCenter, "  ab  \n"},
+		{AlignRight, "    ab\n"},
+	}
+	for _, tt := range tests {
+		opts := RenderOptions{Width: 6, Align: tt.align}
+		if got := RenderInputOptions("ab", wrapBanner, opts); got != tt.want {
+			t.Errorf("align %d: expected %q, got %q", tt.align, tt.want, got)
+		}
+	}
+}
+
+// test that without a width lines are lined up with the widest line
+func TestRenderAlignWidestLine(t *testing.T) {
+	opts := RenderOptions{Align: AlignRight}
+	got := RenderInputOptions("abcd\\nab\\n\\nc", wrapBanner, opts)
+	want := "abcd\n  ab\n\n   c\n"
+	if got != want {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+}
+
+// test that justify widens the gaps between words, first gaps first,
+// and leaves the end of a wrapped line alone
+func TestRenderJustify(t *testing.T) {
+	opts := RenderOptions{Width: 8, Align: AlignJustify}
+	if got, want := RenderInputOptions("a b c", wrapBanner, opts), "a   b  c\n"; got != want {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+
+	opts.Width = 5
+	if got, want := RenderInputOptions("ab c dd", wrapBanner, opts), "ab  c\ndd\n"; got != want {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+}
+
+// test that padding is never inside a color span
+func TestRenderAlignColor(t *testing.T) {
+	opts := RenderOptions{Width: 5, Align: AlignJustify}
+	got := RenderWithColorOptions("a b", wrapBanner, "\033[31m", []int{0, 1, 2}, opts)
+	want := "\033[31ma\033[0m\033[31m \033[0m  \033[31mb\033[0m\n"
+	if got != want {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+
+	opts.Align = AlignCenter
+	got = RenderWithColorOptions("ab", wrapBanner, "\033[31m", []int{0, 1}, opts)
+	want = " \033[31ma\033[0m\033[31mb\033[0m  \n"
+	if got != want {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+}
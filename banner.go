@@ -1,42 +1,190 @@
 package main
 
 import (
+	"bufio"
+	"embed"
 	"fmt"
+	"io"
+	"io/fs"
 	"os"
+	"slices"
+	"strconv"
 	"strings"
+	"unicode"
+	"unicode/utf8"
 )
 
+// builtinBanners holds the bundled banner files inside the binary,
+// so the program works no matter which directory it is run from
+//
+//go:embed banners/*.txt
+var builtinBanners embed.FS
+
 // Banner is a map that holds ASCII art for each character
-// each character maps to a slice of 8 strings (the art lines)
+// each character maps to a slice of strings (the art lines),
+// all characters in one banner have the same number of lines
 type Banner map[rune][]string
 
 const (
 	firstChar  = 32  // space character (first printable ASCII)
 	lastChar   = 126 // ~ character (last printable ASCII)
-	charHeight = 8   // each character is 8 lines tall
+	charHeight = 8   // default height, used when a banner has no glyphs
+	charCount  = lastChar - firstChar + 1
 )
 
-// LoadBanner reads a banner file and loads all the character art
-// banner files have each character as 9 lines (1 empty + 8 art)
+// Height returns how many lines tall the characters of this banner are
+// the tallest glyph wins; an empty banner falls back to charHeight
+func (b Banner) Height() int {
+	height := 0
+	for _, glyph := range b {
+		height = max(height, len(glyph))
+	}
+	if height == 0 {
+		return charHeight
+	}
+	return height
+}
+
+// tabWidth is how many spaces wide a tab is drawn, in banners that
+// don't have a glyph of their own for it
+const tabWidth = 4
+
+// Glyph returns the rows ch is drawn with, and false if the banner doesn't
+// have it; a tab the banner doesn't have is drawn as tabWidth spaces
+func (b Banner) Glyph(ch rune) ([]string, bool) {
+	if glyph, ok := b[ch]; ok || ch != '\t' {
+		return glyph, ok
+	}
+	space, ok := b[' ']
+	if !ok {
+		return nil, false
+	}
+	glyph := make([]string, len(space))
+	for i, row := range space {
+		glyph[i] = strings.Repeat(row, tabWidth)
+	}
+	return glyph, true
+}
+
+// parseGlyphTag reads the separator line of an extra (non-ASCII) character
+// block, like "U+03A9" or "U+03A9 GREEK CAPITAL LETTER OMEGA"
+func parseGlyphTag(line string) (rune, bool) {
+	fields := strings.Fields(line)
+	if len(fields) == 0 {
+		return 0, false
+	}
+	tag := strings.ToUpper(fields[0])
+	if !strings.HasPrefix(tag, "U+") {
+		return 0, false
+	}
+	code, err := strconv.ParseUint(tag[2:], 16, 32)
+	if err != nil || code > unicode.MaxRune {
+		return 0, false
+	}
+	return rune(code), true
+}
+
+// separatorsLineUp checks that with this height every block starts on
+// a separator: an empty line for the 95 ASCII characters, a U+ tag after
+func separatorsLineUp(lines []string, height int) bool {
+	for start, block := 0, 0; start < len(lines); start, block = start+height+1, block+1 {
+		if block < charCount {
+			if lines[start] != "" {
+				return false
+			}
+		} else if _, ok := parseGlyphTag(lines[start]); !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// detectHeight works out how tall each character is in a banner file
+// the file is blocks of (1 separator + height art lines): 95 for ASCII,
+// then optional tagged blocks; we try the heights the line count allows
+// and pick the one where every separator lands in the right place,
+// otherwise we measure the first block (the space character)
+func detectHeight(lines []string) (int, error) {
+	// a final newline leaves one empty string at the end of the split
+	n := len(lines)
+	if n > 0 && lines[n-1] == "" {
+		n--
+	}
+
+	for height := n/charCount - 1; height >= 1; height-- {
+		if n%(height+1) == 0 && separatorsLineUp(lines[:n], height) {
+			return height, nil
+		}
+	}
+
+	for i := 1; i < n; i++ {
+		if lines[i] == "" {
+			if i == 1 {
+				break
+			}
+			return i - 1, nil
+		}
+	}
+	return 0, fmt.Errorf("invalid banner file: cannot detect character height")
+}
+
+// LoadBanner reads a banner file from disk and loads all the character art
+// banner files have each character as 1 empty line followed by its
+// art lines; the number of art lines (the height) is detected from the file
+//
+// after the 95 ASCII characters (space to ~, in order) a file can add
+// any other character as a tagged block: the separator line holds its
+// code point ("U+00E9") instead of being empty, followed by the art lines
+//
+// a file can also start with "# key: value" header lines (see BannerInfo)
 func LoadBanner(path string) (Banner, error) {
+	banner, _, err := LoadBannerInfo(path)
+	return banner, err
+}
+
+// LoadBannerInfo is like LoadBanner but also returns the file's header info
+func LoadBannerInfo(path string) (Banner, BannerInfo, error) {
 	// read the whole file
 	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, BannerInfo{}, err
+	}
+	return parseBannerInfo(data)
+}
+
+// LoadBannerFS is like LoadBanner but reads the file from any fs.FS,
+// e.g. the embedded banners or os.DirFS for a folder on disk
+func LoadBannerFS(fsys fs.FS, path string) (Banner, error) {
+	data, err := fs.ReadFile(fsys, path)
 	if err != nil {
 		return nil, err
 	}
+	return parseBanner(data)
+}
 
+// glyphBlock is one character's art as it appears in a banner file
+type glyphBlock struct {
+	char rune
+	line int      // line number (1-based) of the block's separator line
+	rows []string // the art lines, starting on the line after the separator
+}
+
+// splitBannerLines handles line endings and splits a banner file into lines
+func splitBannerLines(data []byte) []string {
 	// Convert to string and handle both Unix (\n) and Windows (\r\n) line endings
 	// Replace \r\n with \n first, then remove any remaining \r
 	content := strings.ReplaceAll(string(data), "\r\n", "\n")
 	content = strings.ReplaceAll(content, "\r", "\n")
 
 	// split file into lines
-	lines := strings.Split(content, "\n")
-
-	// create the banner map
-	banner := make(Banner)
+	return strings.Split(content, "\n")
+}
 
-	const blockSize = charHeight + 1 // each char = 9 lines total
+// readGlyphBlocks cuts the lines of a banner file into one block per character
+// offset is how many header lines came before these, for line numbers
+func readGlyphBlocks(lines []string, height, offset int) ([]glyphBlock, error) {
+	blockSize := height + 1 // each char = separator + art lines
+	var blocks []glyphBlock
 
 	// go through each ASCII character from space to ~
 	for code := firstChar; code <= lastChar; code++ {
@@ -45,14 +193,299 @@ func LoadBanner(path string) (Banner, error) {
 		start := blockIndex * blockSize
 
 		// make sure we have enough lines in the file
-		if start+1+charHeight > len(lines) {
+		if start+1+height > len(lines) {
 			return nil, fmt.Errorf("invalid banner file: not enough lines for char %q", rune(code))
 		}
 
-		// skip the first empty line, take the next 8 lines
-		glyphLines := lines[start+1 : start+1+charHeight]
-		banner[rune(code)] = glyphLines
+		// skip the first empty line, take the next height lines
+		glyphLines := lines[start+1 : start+1+height]
+		blocks = append(blocks, glyphBlock{char: rune(code), line: offset + start + 1, rows: glyphLines})
+	}
+
+	// then any tagged blocks for characters beyond ASCII
+	for start := charCount * blockSize; start < len(lines); start += blockSize {
+		// blank lines at the very end are not a block
+		if strings.TrimSpace(strings.Join(lines[start:], "")) == "" {
+			break
+		}
+		code, ok := parseGlyphTag(lines[start])
+		if !ok {
+			return nil, fmt.Errorf("invalid banner file: line %d: expected a U+XXXX tag, got %q", offset+start+1, lines[start])
+		}
+		if start+1+height > len(lines) {
+			return nil, fmt.Errorf("invalid banner file: not enough lines for char %q", code)
+		}
+		blocks = append(blocks, glyphBlock{char: code, line: offset + start + 1, rows: lines[start+1 : start+1+height]})
+	}
+
+	return blocks, nil
+}
+
+// parseBanner turns the contents of a banner file into a Banner
+func parseBanner(data []byte) (Banner, error) {
+	banner, _, err := parseBannerInfo(data)
+	return banner, err
+}
+
+// parseBannerInfo does the work for parseBanner, also returning the header
+func parseBannerInfo(data []byte) (Banner, BannerInfo, error) {
+	blocks, info, err := parseBannerBlocks(data)
+	if err != nil {
+		return nil, info, err
+	}
+
+	// create the banner map
+	banner := make(Banner)
+	for _, block := range blocks {
+		banner[block.char] = block.rows
+	}
+
+	return banner, info, nil
+}
+
+// parseBannerBlocks reads the header and cuts the rest of a banner file into
+// character blocks, keeping where each one is in the file (for inspect)
+func parseBannerBlocks(data []byte) ([]glyphBlock, BannerInfo, error) {
+	lines := splitBannerLines(data)
+
+	info, headerLines, err := parseBannerHeader(lines)
+	if err != nil {
+		return nil, info, fmt.Errorf("invalid banner file: line %d: %v", headerLines, err)
+	}
+	lines = lines[headerLines:]
+
+	// a declared height wins, otherwise work it out from the file
+	height := info.Height
+	if height == 0 {
+		height, err = detectHeight(lines)
+		if err != nil {
+			return nil, info, err
+		}
+	}
+
+	blocks, err := readGlyphBlocks(lines, height, headerLines)
+	return blocks, info, err
+}
+
+// WriteBanner writes a banner in the file format LoadBanner reads:
+// the 95 ASCII characters in order, then a tagged block for every other
+// character, sorted by code point
+//
+// rows are padded to the glyph's width so no art line is empty (an empty
+// line would look like a separator); missing ASCII characters are blank
+func WriteBanner(w io.Writer, b Banner) error {
+	return WriteBannerInfo(w, b, BannerInfo{})
+}
+
+// WriteBannerInfo is like WriteBanner but starts the file with a header
+// for every field of info that is set
+func WriteBannerInfo(w io.Writer, b Banner, info BannerInfo) error {
+	height := b.Height()
+	out := bufio.NewWriter(w)
+
+	if err := writeBannerHeader(out, info); err != nil {
+		return err
+	}
+
+	writeBlock := func(separator string, glyph []string) {
+		out.WriteString(separator + "\n")
+		width := max(glyphWidth(glyph), 1)
+		for row := 0; row < height; row++ {
+			line := ""
+			if row < len(glyph) {
+				line = strings.ReplaceAll(glyph[row], string(hardblank), " ")
+			}
+			out.WriteString(line + strings.Repeat(" ", width-utf8.RuneCountInString(line)) + "\n")
+		}
+	}
+
+	for code := firstChar; code <= lastChar; code++ {
+		writeBlock("", b[rune(code)])
+	}
+
+	var extra []rune
+	for ch := range b {
+		if ch < firstChar || ch > lastChar {
+			extra = append(extra, ch)
+		}
+	}
+	slices.Sort(extra)
+	for _, ch := range extra {
+		writeBlock(fmt.Sprintf("U+%04X", ch), b[ch])
+	}
+
+	return out.Flush()
+}
+
+// BannerIssue is one problem found by ValidateBanner
+type BannerIssue struct {
+	Line    int  // line number in the file (1-based)
+	Char    rune // the character the problem belongs to, 0 if none
+	Message string
+}
+
+// String formats the issue like "line 12: 'A': row 3 is 7 wide, expected 6"
+func (issue BannerIssue) String() string {
+	if issue.Char == 0 {
+		return fmt.Sprintf("line %d: %s", issue.Line, issue.Message)
+	}
+	return fmt.Sprintf("line %d: %q: %s", issue.Line, issue.Char, issue.Message)
+}
+
+// ValidateBanner checks a banner file for problems that LoadBanner lets
+// through or only reports vaguely: ragged row widths, missing separator
+// lines, non-printable characters (like tabs), trailing-line problems and
+// characters defined twice; an empty result means the file is fine
+func ValidateBanner(path string) ([]BannerIssue, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return validateBannerData(data), nil
+}
+
+// validateBannerData does the work for ValidateBanner
+// unlike parseBanner it keeps going after a problem, so one run
+// reports everything; after a broken block it re-syncs on the next separator
+func validateBannerData(data []byte) []BannerIssue {
+	var issues []BannerIssue
+	report := func(line int, char rune, format string, args ...any) {
+		issues = append(issues, BannerIssue{Line: line, Char: char, Message: fmt.Sprintf(format, args...)})
+	}
+
+	lines := splitBannerLines(data)
+	n := len(lines)
+	if n > 0 && lines[n-1] == "" {
+		n--
+	} else if len(data) > 0 {
+		report(n, 0, "file does not end with a newline")
 	}
 
-	return banner, nil
+	// the header comes first; glyph blocks start after it
+	info, headerLines, err := parseBannerHeader(lines)
+	if err != nil {
+		report(headerLines, 0, "%v", err)
+		// carry on after the rest of the header
+		for headerLines < len(lines) && strings.HasPrefix(lines[headerLines], "#") {
+			headerLines++
+		}
+	}
+
+	height := info.Height
+	if height == 0 {
+		height, err = detectHeight(lines[headerLines:])
+		if err != nil {
+			report(headerLines+1, 0, "%v", err)
+			return issues
+		}
+	}
+	if info.Baseline > height {
+		report(headerLines, 0, "baseline %d is below the last row (height %d)", info.Baseline, height)
+	}
+
+	// isSeparator reports whether a line can start the block after this one
+	isSeparator := func(line string, block int) bool {
+		if block < charCount {
+			return line == ""
+		}
+		_, ok := parseGlyphTag(line)
+		return ok
+	}
+
+	// restBlank reports whether everything from line pos on is blank
+	restBlank := func(pos int) bool {
+		return strings.TrimSpace(strings.Join(lines[pos:n], "")) == ""
+	}
+
+	defined := make(map[rune]int) // char -> line it was first defined on
+	block := 0
+	pos := headerLines
+	for pos < n {
+		// the rest of the file is blank: nothing more to read
+		if block >= charCount && restBlank(pos) {
+			report(pos+1, 0, "%d extra blank line(s) at end of file", n-pos)
+			break
+		}
+
+		// work out which character this block is for
+		var char rune
+		if block < charCount {
+			char = rune(firstChar + block)
+			if lines[pos] != "" {
+				report(pos+1, char, "missing separator: expected an empty line, got %q", lines[pos])
+			}
+		} else {
+			tag, ok := parseGlyphTag(lines[pos])
+			if !ok {
+				report(pos+1, 0, "missing separator: expected a U+XXXX tag, got %q", lines[pos])
+				// skip ahead to something that looks like a tag
+				for pos++; pos < n && !isSeparator(lines[pos], block); pos++ {
+				}
+				continue
+			}
+			char = tag
+		}
+
+		if first, dup := defined[char]; dup {
+			report(pos+1, char, "defined again (first defined on line %d)", first)
+		} else {
+			defined[char] = pos + 1
+		}
+
+		// read the art lines, stopping early if we run into a separator
+		var rows []string
+		for len(rows) < height && pos+1+len(rows) < n && lines[pos+1+len(rows)] != "" {
+			rows = append(rows, lines[pos+1+len(rows)])
+		}
+
+		// rows should all be as wide as most of the others
+		width := commonWidth(rows)
+		for i, row := range rows {
+			lineNo := pos + 2 + i
+			for col, r := range []rune(row) {
+				if !unicode.IsPrint(r) {
+					report(lineNo, char, "non-printable character %q at column %d", r, col+1)
+				}
+			}
+			if rowWidth := utf8.RuneCountInString(row); rowWidth != width {
+				report(lineNo, char, "row %d is %d wide, the other rows are %d", i+1, rowWidth, width)
+			}
+		}
+		if len(rows) < height {
+			report(pos+1, char, "only %d art line(s), expected %d", len(rows), height)
+		}
+		pos += 1 + len(rows)
+		block++
+
+		// the next block should start right here; if not, this glyph
+		// has extra lines, so skip them and re-sync on the next separator
+		if pos < n && !isSeparator(lines[pos], block) && !restBlank(pos) {
+			extra := pos
+			for pos < n && !isSeparator(lines[pos], block) {
+				pos++
+			}
+			report(extra+1, char, "%d extra line(s) after the art (missing separator?)", pos-extra)
+		}
+	}
+
+	if block < charCount {
+		report(n, 0, "file ends early: characters %q to %q are missing", rune(firstChar+block), rune(lastChar))
+	}
+
+	return issues
+}
+
+// commonWidth returns the width (in characters) shared by most rows
+// ties go to the width that shows up first
+func commonWidth(rows []string) int {
+	counts := make(map[int]int)
+	best := 0
+	for _, row := range rows {
+		w := utf8.RuneCountInString(row)
+		counts[w]++
+		if counts[w] > counts[best] {
+			best = w
+		}
+	}
+	return best
 }
@@ -0,0 +1,208 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// defaultInk is the character set pixels are drawn with when importing
+// a bitmap font and nothing else was asked for
+const defaultInk = '#'
+
+// LoadBDF reads an X11 BDF bitmap font and turns it into a Banner,
+// drawing every set pixel with ink and every clear pixel with a space
+// all characters get the height of the font's bounding box
+func LoadBDF(path string, ink rune) (Banner, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return parseBDF(string(data), ink)
+}
+
+// isBDFPath reports whether a banner argument points to a BDF font file
+func isBDFPath(name string) bool {
+	return strings.HasSuffix(strings.ToLower(name), ".bdf")
+}
+
+// bdfBox is a bounding box: width, height and the offset of its
+// bottom-left corner from the character's origin (on the baseline)
+type bdfBox struct {
+	width, height, x, y int
+}
+
+// parseBDFNumbers reads the numbers after a BDF keyword, e.g. "BBX 8 16 0 -4"
+func parseBDFNumbers(fields []string, count int) ([]int, error) {
+	if len(fields) < count+1 {
+		return nil, fmt.Errorf("%s needs %d numbers", fields[0], count)
+	}
+	numbers := make([]int, count)
+	for i := range numbers {
+		n, err := strconv.Atoi(fields[i+1])
+		if err != nil {
+			return nil, fmt.Errorf("%s: bad number %q", fields[0], fields[i+1])
+		}
+		numbers[i] = n
+	}
+	return numbers, nil
+}
+
+// parseBDF does the actual work for LoadBDF
+func parseBDF(content string, ink rune) (Banner, error) {
+	banner, _, err := parseBDFInfo(content, ink)
+	return banner, err
+}
+
+// parseBDFInfo is parseBDF that also returns the font's name, height
+// and baseline as a BannerInfo
+func parseBDFInfo(content string, ink rune) (Banner, BannerInfo, error) {
+	content = strings.ReplaceAll(content, "\r\n", "\n")
+	lines := strings.Split(content, "\n")
+
+	if len(lines) == 0 || !strings.HasPrefix(lines[0], "STARTFONT") {
+		return nil, BannerInfo{}, fmt.Errorf("invalid BDF font: missing STARTFONT")
+	}
+
+	banner := make(Banner)
+	info := BannerInfo{Ink: string(ink)}
+	var font bdfBox
+	haveFont := false
+
+	// the character being read
+	var (
+		encoding = -1
+		advance  int
+		box      bdfBox
+		bitmap   []string
+		inBitmap bool
+	)
+
+	for i, line := range lines {
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		lineErr := func(err error) error {
+			return fmt.Errorf("invalid BDF font: line %d: %v", i+1, err)
+		}
+
+		if inBitmap && fields[0] != "ENDCHAR" {
+			bitmap = append(bitmap, fields[0])
+			continue
+		}
+
+		switch fields[0] {
+		case "FONT":
+			info.Name = strings.TrimSpace(strings.TrimPrefix(line, "FONT"))
+		case "FONTBOUNDINGBOX":
+			n, err := parseBDFNumbers(fields, 4)
+			if err != nil {
+				return nil, BannerInfo{}, lineErr(err)
+			}
+			font = bdfBox{n[0], n[1], n[2], n[3]}
+			if font.width < 1 || font.height < 1 {
+				return nil, BannerInfo{}, lineErr(fmt.Errorf("font bounding box must be at least 1x1, got %dx%d", font.width, font.height))
+			}
+			haveFont = true
+			// rows from the top of the box down to the baseline
+			info.Height = font.height
+			info.Baseline = max(font.height+font.y, 0)
+		case "STARTCHAR":
+			encoding, advance, box, bitmap = -1, 0, bdfBox{}, nil
+		case "ENCODING":
+			n, err := parseBDFNumbers(fields, 1)
+			if err != nil {
+				return nil, BannerInfo{}, lineErr(err)
+			}
+			encoding = n[0]
+		case "DWIDTH":
+			n, err := parseBDFNumbers(fields, 1)
+			if err != nil {
+				return nil, BannerInfo{}, lineErr(err)
+			}
+			advance = n[0]
+			if advance < 0 {
+				return nil, BannerInfo{}, lineErr(fmt.Errorf("negative DWIDTH %d", advance))
+			}
+		case "BBX":
+			n, err := parseBDFNumbers(fields, 4)
+			if err != nil {
+				return nil, BannerInfo{}, lineErr(err)
+			}
+			box = bdfBox{n[0], n[1], n[2], n[3]}
+			if box.width < 0 || box.height < 0 {
+				return nil, BannerInfo{}, lineErr(fmt.Errorf("negative BBX size %dx%d", box.width, box.height))
+			}
+		case "BITMAP":
+			if !haveFont {
+				return nil, BannerInfo{}, lineErr(fmt.Errorf("BITMAP before FONTBOUNDINGBOX"))
+			}
+			inBitmap = true
+		case "ENDCHAR":
+			inBitmap = false
+			// negative encodings are unnamed extra glyphs, nothing to map them to
+			if encoding < 0 {
+				continue
+			}
+			rows, err := bdfGlyph(font, box, advance, bitmap, ink)
+			if err != nil {
+				return nil, BannerInfo{}, lineErr(err)
+			}
+			banner[rune(encoding)] = rows
+		}
+	}
+
+	if len(banner) == 0 {
+		return nil, BannerInfo{}, fmt.Errorf("invalid BDF font: no characters found")
+	}
+	return banner, info, nil
+}
+
+// bdfGlyph draws one character's bitmap into rows of text
+// every character is drawn in a cell as tall as the font's bounding box,
+// lined up on the baseline, and as wide as its advance (DWIDTH)
+func bdfGlyph(font, box bdfBox, advance int, bitmap []string, ink rune) ([]string, error) {
+	// the font box may reach left of the origin; shift everything right so
+	// all characters share the same left edge
+	left := -min(0, font.x)
+	width := max(left+advance, left+box.x+box.width)
+
+	cells := make([][]rune, font.height)
+	for row := range cells {
+		cells[row] = []rune(strings.Repeat(" ", width))
+	}
+
+	// row 0 of the cell is the top of the font box
+	top := font.y + font.height - 1
+	for r, hex := range bitmap {
+		if r >= box.height {
+			break
+		}
+		bits, err := strconv.ParseUint(hex, 16, 64)
+		if err != nil || len(hex) > 16 {
+			return nil, fmt.Errorf("bad bitmap row %q", hex)
+		}
+		row := top - (box.y + box.height - 1) + r
+		if row < 0 || row >= font.height {
+			continue
+		}
+		// rows are padded to whole bytes, most significant bit first
+		rowBits := len(hex) * 4
+		for c := 0; c < box.width && c < rowBits; c++ {
+			if bits&(1<<(rowBits-1-c)) != 0 {
+				col := left + box.x + c
+				if col >= 0 && col < width {
+					cells[row][col] = ink
+				}
+			}
+		}
+	}
+
+	rows := make([]string, font.height)
+	for i, cellRow := range cells {
+		rows[i] = string(cellRow)
+	}
+	return rows, nil
+}
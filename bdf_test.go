@@ -0,0 +1,136 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+// a tiny BDF font: H sits on the baseline, j hangs one row below it
+const testBDF = `STARTFONT 2.1
+FONT -test-
+FONTBOUNDINGBOX 5 7 0 -1
+CHARS 2
+STARTCHAR H
+ENCODING 72
+DWIDTH 6 0
+BBX 5 6 0 0
+BITMAP
+88
+88
+F8
+88
+88
+88
+ENDCHAR
+STARTCHAR j
+ENCODING 106
+DWIDTH 6 0
+BBX 3 7 1 -1
+BITMAP
+20
+00
+20
+20
+20
+A0
+40
+ENDCHAR
+ENDFONT
+`
+
+// test that pixels end up in the right rows and columns
+func TestParseBDF(t *testing.T) {
+	banner, err := parseBDF(testBDF, '#')
+	if err != nil {
+		t.Fatalf("failed to parse font: %v", err)
+	}
+
+	if banner.Height() != 7 {
+		t.Errorf("expected height 7, got %d", banner.Height())
+	}
+
+	wantH := []string{"#   # ", "#   # ", "##### ", "#   # ", "#   # ", "#   # ", "      "}
+	wantJ := []string{"   #  ", "      ", "   #  ", "   #  ", "   #  ", " # #  ", "  #   "}
+	for row := range wantH {
+		if banner['H'][row] != wantH[row] {
+			t.Errorf("H row %d: got %q, want %q", row, banner['H'][row], wantH[row])
+		}
+		if banner['j'][row] != wantJ[row] {
+			t.Errorf("j row %d: got %q, want %q", row, banner['j'][row], wantJ[row])
+		}
+	}
+}
+
+// test that the ink character can be changed
+func TestParseBDFInk(t *testing.T) {
+	banner, err := parseBDF(testBDF, '@')
+	if err != nil {
+		t.Fatalf("failed to parse font: %v", err)
+	}
+	if banner['H'][2] != "@@@@@ " {
+		t.Errorf("got %q", banner['H'][2])
+	}
+}
+
+// test that a written banner loads back the same
+func TestWriteBannerRoundTrip(t *testing.T) {
+	banner, err := parseBDF(testBDF, '#')
+	if err != nil {
+		t.Fatalf("failed to parse font: %v", err)
+	}
+	banner['Ω'] = banner['H']
+
+	var buf bytes.Buffer
+	if err := WriteBanner(&buf, banner); err != nil {
+		t.Fatalf("failed to write banner: %v", err)
+	}
+	if issues := validateBannerData(buf.Bytes()); len(issues) != 0 {
+		t.Errorf("written banner has problems: %v", issues)
+	}
+
+	loaded, err := parseBanner(buf.Bytes())
+	if err != nil {
+		t.Fatalf("failed to load written banner: %v", err)
+	}
+	for _, ch := range []rune{'H', 'j', 'Ω'} {
+		for row := range banner[ch] {
+			if loaded[ch][row] != banner[ch][row] {
+				t.Errorf("%q row %d: got %q, want %q", ch, row, loaded[ch][row], banner[ch][row])
+			}
+		}
+	}
+}
+
+// test that files without STARTFONT are rejected
+func TestParseBDFInvalid(t *testing.T) {
+	if _, err := parseBDF("hello\n", '#'); err == nil {
+		t.Error("expected error for missing STARTFONT")
+	}
+}
+
+// test that impossible sizes are errors with a line number, not a crash
+func TestParseBDFBadSizes(t *testing.T) {
+	tests := []struct {
+		name    string
+		from    string
+		to      string
+		message string
+	}{
+		{"font box height", "FONTBOUNDINGBOX 5 7 0 -1", "FONTBOUNDINGBOX 4 -2 0 0", "line 3:"},
+		{"font box width", "FONTBOUNDINGBOX 5 7 0 -1", "FONTBOUNDINGBOX 0 7 0 -1", "line 3:"},
+		{"DWIDTH", "DWIDTH 6 0\nBBX 5 6 0 0", "DWIDTH -9 0\nBBX 5 6 0 0", "line 7:"},
+		{"BBX", "BBX 5 6 0 0", "BBX -4 1 0 0", "line 8:"},
+	}
+	for _, tt := range tests {
+		font := strings.Replace(testBDF, tt.from, tt.to, 1)
+		_, err := parseBDF(font, '#')
+		if err == nil {
+			t.Errorf("%s: expected an error", tt.name)
+			continue
+		}
+		if !strings.Contains(err.Error(), "invalid BDF font: "+tt.message) {
+			t.Errorf("%s: expected the error on %s got %q", tt.name, tt.message, err)
+		}
+	}
+}
@@ -0,0 +1,111 @@
+package main
+
+import "unicode"
+
+// Right-to-left text (Hebrew, Arabic, ...) is drawn with its first character
+// on the right; numbers and Latin words inside it still read left to right
+// this is a small version of the Unicode bidi algorithm: each character gets
+// a level (even = left to right, odd = right to left) and runs of higher
+// levels are reversed
+
+// isRTLRune reports whether a character belongs to a right-to-left script
+func isRTLRune(r rune) bool {
+	return unicode.In(r, unicode.Hebrew, unicode.Arabic, unicode.Syriac, unicode.Thaana, unicode.Nko)
+}
+
+// isLTRRune reports whether a character is strongly left to right
+// (letters of other scripts, and digits)
+func isLTRRune(r rune) bool {
+	return (unicode.IsLetter(r) || unicode.IsDigit(r)) && !isRTLRune(r)
+}
+
+// startsRTL reports whether the first letter of a line is right to left,
+// which decides the line's direction for --direction=auto
+func startsRTL(chars []rune) bool {
+	for _, r := range chars {
+		if isRTLRune(r) {
+			return true
+		}
+		if isLTRRune(r) {
+			return false
+		}
+	}
+	return false
+}
+
+// bidiMirrors are the characters drawn the other way round inside
+// right-to-left text, so an opening bracket still opens
+var bidiMirrors = swapPairs("()", "[]", "{}", "<>")
+
+// bidiLevels gives every character its level in a line whose direction is
+// rtl; spaces and punctuation take the level of the letters on both sides
+// when those agree, and the line's level otherwise
+func bidiLevels(chars []rune, rtl bool) []int {
+	base, ltr := 0, 0
+	if rtl {
+		// left-to-right text inside right-to-left text is one level up
+		base, ltr = 1, 2
+	}
+
+	levels := make([]int, len(chars))
+	for i, r := range chars {
+		switch {
+		case isRTLRune(r):
+			levels[i] = 1
+		case isLTRRune(r):
+			levels[i] = ltr
+		default:
+			levels[i] = -1 // neutral, worked out below
+		}
+	}
+
+	for i := 0; i < len(levels); i++ {
+		if levels[i] != -1 {
+			continue
+		}
+		// find the run of neutrals and the letters on each side of it
+		end := i
+		for end < len(levels) && levels[end] == -1 {
+			end++
+		}
+		level := base
+		if i > 0 && end < len(levels) && levels[i-1] == levels[end] {
+			level = levels[end]
+		}
+		for j := i; j < end; j++ {
+			levels[j] = level
+		}
+		i = end - 1
+	}
+	return levels
+}
+
+// bidiOrder returns the positions of the characters in the order they are
+// drawn, left to right, and the level of each character
+func bidiOrder(chars []rune, rtl bool) ([]int, []int) {
+	levels := bidiLevels(chars, rtl)
+	order := make([]int, len(chars))
+	highest := 0
+	for i := range order {
+		order[i] = i
+		highest = max(highest, levels[i])
+	}
+
+	// from the highest level down to 1, reverse every run at that level or above
+	for level := highest; level >= 1; level-- {
+		for start := 0; start < len(order); start++ {
+			if levels[order[start]] < level {
+				continue
+			}
+			end := start
+			for end < len(order) && levels[order[end]] >= level {
+				end++
+			}
+			for a, b := start, end-1; a < b; a, b = a+1, b-1 {
+				order[a], order[b] = order[b], order[a]
+			}
+			start = end
+		}
+	}
+	return order, levels
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"slices"
+	"testing"
+)
+
+// test the bidi order of mixed right-to-left and left-to-right text
+func TestBidiOrder(t *testing.T) {
+	tests := []struct {
+		text string
+		rtl  bool
+		want []int
+	}{
+		// Hebrew alef bet: drawn bet alef
+		{"אב", true, []int{1, 0}},
+		// the number inside keeps its order, the words around it swap
+		{"א 12 ב", true, []int{5, 4, 2, 3, 1, 0}},
+		// a Hebrew word inside English is reversed on its own
+		{"ab אב", false, []int{0, 1, 2, 4, 3}},
+		// plain English is untouched
+		{"a b", false, []int{0, 1, 2}},
+	}
+	for _, tt := range tests {
+		got, _ := bidiOrder([]rune(tt.text), tt.rtl)
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("%q: expected %v, got %v", tt.text, tt.want, got)
+		}
+	}
+}
+
+// a banner with one-column glyphs, including two Hebrew letters
+var bidiBanner = Banner{
+	'a': {"a"}, 'b': {"b"}, 'c': {"c"}, '(': {"("}, ')': {")"},
+	'א': {"A"}, 'ב': {"B"},
+}
+
+// test that rtl draws every character right to left, mirroring brackets
+func TestRenderLineRTL(t *testing.T) {
+	opts := RenderOptions{Direction: DirectionRTL}
+	if got := RenderLineOptions("a(b", bidiBanner, opts); got != "b)a\n" {
+		t.Errorf("expected %q, got %q", "b)a\n", got)
+	}
+}
+
+// test that colors stay on the characters they were found on
+func TestRenderRTLColor(t *testing.T) {
+	opts := RenderOptions{Direction: DirectionRTL}
+	// color position 0, the a, which is drawn last
+	got := RenderWithColorOptions("abc", bidiBanner, "\033[31m", []int{0}, opts)
+	want := "cb\033[31ma\033[0m\n"
+	if got != want {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+}
+
+// test that right-to-left lines are right-aligned and auto picks
+// the direction of each line
+func TestRenderAutoLines(t *testing.T) {
+	opts := RenderOptions{Direction: DirectionAuto}
+	got := RenderInputOptions("abc\\nאב", bidiBanner, opts)
+	want := "abc\n BA\n"
+	if got != want {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+
+	opts.Direction = DirectionRTL
+	got = RenderInputOptions("abc\\nab", bidiBanner, opts)
+	want = "cba\n ba\n"
+	if got != want {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+}
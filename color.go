@@ -2,7 +2,9 @@ package main
 
 import (
 	"fmt"
+	"strconv"
 	"strings"
+	"unicode/utf8"
 )
 
 // ANSI color codes map
@@ -29,20 +31,25 @@ func GetColorCode(colorName string) (string, bool) {
 }
 
 // FindSubstringIndexes finds all character positions of substring in text
+// positions count characters (runes), not bytes, so "é" is one position
+// just like in the renderers
 func FindSubstringIndexes(text, substring string) []int {
+	chars := []rune(text)
+
 	if substring == "" {
 		// Color everything
-		result := make([]int, len(text))
-		for i := range text {
+		result := make([]int, len(chars))
+		for i := range chars {
 			result[i] = i
 		}
 		return result
 	}
 
+	sub := []rune(substring)
 	var indexes []int
-	for i := 0; i <= len(text)-len(substring); i++ {
-		if text[i:i+len(substring)] == substring {
-			for j := 0; j < len(substring); j++ {
+	for i := 0; i <= len(chars)-len(sub); i++ {
+		if string(chars[i:i+len(sub)]) == substring {
+			for j := 0; j < len(sub); j++ {
 				indexes = append(indexes, i+j)
 			}
 		}
@@ -71,26 +78,77 @@ type ColorOptions struct {
 	Text                 string
 	Banner               string
 	OutputFile           string
+	// BannerDirs: extra folders to look for banners in (--banner-dir=, can repeat)
+	BannerDirs []string
+	// Fallbacks: banners to borrow missing characters from, in order (--fallback=a,b)
+	Fallbacks []string
+	// Layout: how characters are joined (--layout=full|fit|smush|...)
+	// LayoutProvided: --layout was given; if not, the banner's default is used
+	Layout         Layout
+	LayoutProvided bool
+	// Scale: how many times bigger to draw the characters (--scale=2 or --scale=3x1)
+	Scale Scale
+	// Transforms: mirror, flip or rotate the finished art (--transform=mirror,rotate90)
+	Transforms []Transform
+	// Shear: lean the rows to fake italics (--shear=1)
+	// Wave: move the columns up and down (--wave=2 or --wave=2,12)
+	Shear int
+	Wave  Wave
+	// Shadow: a shadow (--shadow=dx,dy) or 3D depth (--extrude=dx,dy) behind
+	// the letters, drawn with --shadow-char=<char> in --shadow-color=<name>
+	Shadow Shadow
+	// Direction: --direction=vertical stacks the characters top to bottom,
+	// --spacing=<rows> apart
+	Direction Direction
+	Spacing   int
+	// Width: wrap lines wider than this (--width=N, 0 for no wrapping)
+	// WidthProvided: --width was given; if not, the terminal's width is used
+	// Align: line each line up in the width (--align=left|center|right|justify)
+	Width         int
+	WidthProvided bool
+	Align         Align
+	// InputFile: read the text from this file instead of the arguments
+	// (--input=<file>); "-" is stdin, which is also used when the text
+	// argument is - or there are no arguments and stdin is piped
+	InputFile string
+	// NoEscapes: use the text and substring exactly as typed, without
+	// turning \n, \t, \\, \xHH and \uXXXX into characters (--no-escapes)
+	NoEscapes bool
 }
 
 // ParseColorArgs parses command line arguments
 // Simple version - easier to understand!
 func ParseColorArgs(args []string) (ColorOptions, error) {
+	return ParseColorArgsStdin(args, false)
+}
+
+// ParseColorArgsStdin is ParseColorArgs for when stdin may be piped in:
+// if stdinPiped is true and no text is given, the text is read from stdin
+func ParseColorArgsStdin(args []string, stdinPiped bool) (ColorOptions, error) {
 	opts := ColorOptions{
 		UseColor: false,
 		Banner:   "standard",
 	}
 
 	// args[0] is program name
-	if len(args) < 2 {
+	if len(args) < 2 && !stdinPiped {
 		return opts, fmt.Errorf("need at least one argument (text to render)")
 	}
 
 	// Start from index 1
 	i := 1
 
-	// Check for flags (--color= or --output=)
+	// Check for flags (--color=, --output=, --banner-dir=, --fallback=, --layout=,
+	// --scale=, --transform=, --shear=, --wave=, --shadow=, --extrude=,
+	// --shadow-char=, --shadow-color=, --direction=, --spacing=, --align=,
+	// --width=, --input= or --no-escapes)
 	for i < len(args) && strings.HasPrefix(args[i], "--") {
+		// -- ends the options: what comes after is the text (and banner)
+		// even if it starts with -- or is a subcommand name like validate
+		if args[i] == "--" {
+			i++
+			break
+		}
 		if strings.HasPrefix(args[i], "--color=") {
 			opts.UseColor = true
 			opts.Color = args[i][8:] // After "--color="
@@ -102,20 +160,148 @@ func ParseColorArgs(args []string) (ColorOptions, error) {
 			if opts.OutputFile == "" {
 				return opts, fmt.Errorf("empty output file")
 			}
+		} else if strings.HasPrefix(args[i], "--banner-dir=") {
+			dir := args[i][13:] // After "--banner-dir="
+			if dir == "" {
+				return opts, fmt.Errorf("empty banner directory")
+			}
+			opts.BannerDirs = append(opts.BannerDirs, dir)
+		} else if strings.HasPrefix(args[i], "--fallback=") {
+			for _, name := range strings.Split(args[i][11:], ",") { // After "--fallback="
+				if name == "" {
+					return opts, fmt.Errorf("empty banner name in --fallback")
+				}
+				opts.Fallbacks = append(opts.Fallbacks, name)
+			}
+		} else if strings.HasPrefix(args[i], "--layout=") {
+			layout, err := ParseLayout(args[i][9:]) // After "--layout="
+			if err != nil {
+				return opts, err
+			}
+			opts.Layout = layout
+			opts.LayoutProvided = true
+		} else if strings.HasPrefix(args[i], "--scale=") {
+			scale, err := ParseScale(args[i][8:]) // After "--scale="
+			if err != nil {
+				return opts, err
+			}
+			opts.Scale = scale
+		} else if strings.HasPrefix(args[i], "--transform=") {
+			transforms, err := ParseTransforms(args[i][12:]) // After "--transform="
+			if err != nil {
+				return opts, err
+			}
+			opts.Transforms = append(opts.Transforms, transforms...)
+		} else if strings.HasPrefix(args[i], "--shear=") {
+			shear, err := ParseShear(args[i][8:]) // After "--shear="
+			if err != nil {
+				return opts, err
+			}
+			opts.Shear = shear
+		} else if strings.HasPrefix(args[i], "--wave=") {
+			wave, err := ParseWave(args[i][7:]) // After "--wave="
+			if err != nil {
+				return opts, err
+			}
+			opts.Wave = wave
+		} else if strings.HasPrefix(args[i], "--shadow=") || strings.HasPrefix(args[i], "--extrude=") {
+			name, value, _ := strings.Cut(args[i], "=")
+			dx, dy, err := ParseShadowOffset(value)
+			if err != nil {
+				return opts, err
+			}
+			opts.Shadow.DX, opts.Shadow.DY = dx, dy
+			opts.Shadow.Extrude = name == "--extrude"
+		} else if strings.HasPrefix(args[i], "--shadow-char=") {
+			value := args[i][14:] // After "--shadow-char="
+			if utf8.RuneCountInString(value) != 1 {
+				return opts, fmt.Errorf("--shadow-char needs exactly one character, got %q", value)
+			}
+			opts.Shadow.Char, _ = utf8.DecodeRuneInString(value)
+		} else if strings.HasPrefix(args[i], "--shadow-color=") {
+			code, ok := GetColorCode(args[i][15:]) // After "--shadow-color="
+			if !ok {
+				return opts, fmt.Errorf("invalid shadow color %q", args[i][15:])
+			}
+			opts.Shadow.Color = code
+		} else if strings.HasPrefix(args[i], "--direction=") {
+			direction, err := ParseDirection(args[i][12:]) // After "--direction="
+			if err != nil {
+				return opts, err
+			}
+			opts.Direction = direction
+		} else if strings.HasPrefix(args[i], "--spacing=") {
+			spacing, err := strconv.Atoi(args[i][10:]) // After "--spacing="
+			if err != nil || spacing < 0 || spacing > maxSpacing {
+				return opts, fmt.Errorf("invalid spacing %q (expected a number of rows from 0 to %d)", args[i][10:], maxSpacing)
+			}
+			opts.Spacing = spacing
+		} else if strings.HasPrefix(args[i], "--align=") {
+			align, err := ParseAlign(args[i][8:]) // After "--align="
+			if err != nil {
+				return opts, err
+			}
+			opts.Align = align
+		} else if strings.HasPrefix(args[i], "--width=") {
+			width, err := strconv.Atoi(args[i][8:]) // After "--width="
+			if err != nil || width < 0 {
+				return opts, fmt.Errorf("invalid width %q (expected a number of columns, 0 for no wrapping)", args[i][8:])
+			}
+			opts.Width = width
+			opts.WidthProvided = true
+		} else if args[i] == "--no-escapes" {
+			opts.NoEscapes = true
+		} else if strings.HasPrefix(args[i], "--input=") {
+			opts.InputFile = args[i][8:] // After "--input="
+			if opts.InputFile == "" {
+				return opts, fmt.Errorf("empty input file")
+			}
 		} else {
-			return opts, fmt.Errorf("unknown flag %q (expected --color=<name> or --output=<file>)", args[i])
+			return opts, fmt.Errorf("unknown flag %q (expected --color=<name>, --output=<file>, --banner-dir=<dir>, --fallback=<banners>, --layout=<mode>, --scale=<n>, --transform=<names>, --shear=<n>, --wave=<amplitude>[,<period>], --shadow=<dx,dy>, --extrude=<dx,dy>, --shadow-char=<char>, --shadow-color=<name>, --direction=<way>, --spacing=<rows>, --align=<where>, --width=<columns>, --input=<file> or --no-escapes)", args[i])
 		}
 		i++
 	}
 
-	// Need at least text after flags
-	if i >= len(args) {
+	// Settings that can't be used together, or need another one
+	if opts.Shadow.DX == 0 && opts.Shadow.DY == 0 {
+		if opts.Shadow.Char != 0 {
+			return opts, fmt.Errorf("--shadow-char needs --shadow=<dx,dy> or --extrude=<dx,dy>")
+		}
+		if opts.Shadow.Color != "" {
+			return opts, fmt.Errorf("--shadow-color needs --shadow=<dx,dy> or --extrude=<dx,dy>")
+		}
+	}
+	if opts.Align == AlignJustify && opts.Direction == DirectionVertical {
+		return opts, fmt.Errorf("--align=justify can't be used with --direction=vertical (there are no words in a column to spread out; use left, center or right)")
+	}
+
+	// Nothing after the flags: the text comes from stdin if it's piped
+	if i >= len(args) && opts.InputFile == "" && stdinPiped {
+		opts.InputFile = stdinName
+	}
+
+	// Need at least text after flags (unless it's read from a file)
+	if i >= len(args) && opts.InputFile == "" {
 		return opts, fmt.Errorf("missing text after options")
 	}
 
 	// Now we have remaining arguments
 	remaining := args[i:]
 
+	// Banner names come from the same search path main.go loads from,
+	// so a file dropped into a banner folder is recognised here too
+	registry := NewBannerRegistry(opts.BannerDirs)
+
+	// The text comes from a file, so the arguments are only
+	// [banner], [substring] or [substring, banner]
+	if opts.InputFile != "" {
+		var err error
+		if opts, err = parseInputArgs(opts, remaining, registry); err != nil {
+			return opts, err
+		}
+		return opts, opts.decodeEscapes()
+	}
+
 	switch len(remaining) {
 	case 1:
 		// Just text
@@ -123,7 +309,7 @@ func ParseColorArgs(args []string) (ColorOptions, error) {
 
 	case 2:
 		// Could be: [text, banner] OR [substring, text]
-		if remaining[1] == "standard" || remaining[1] == "shadow" || remaining[1] == "thinkertoy" {
+		if registry.Has(remaining[1]) {
 			// [text, banner]
 			opts.Text = remaining[0]
 			opts.Banner = remaining[1]
@@ -133,7 +319,7 @@ func ParseColorArgs(args []string) (ColorOptions, error) {
 			opts.Substring = remaining[0]
 			opts.Text = remaining[1]
 		} else {
-			return opts, fmt.Errorf("invalid arguments: %q is not a banner (standard/shadow/thinkertoy); use --color for [substring] [text]", remaining[1])
+			return opts, fmt.Errorf("invalid arguments: %q is not a banner (%s); use --color for [substring] [text]", remaining[1], strings.Join(registry.Names(), "/"))
 		}
 
 	case 3:
@@ -150,5 +336,75 @@ func ParseColorArgs(args []string) (ColorOptions, error) {
 		return opts, fmt.Errorf("too many arguments: got %d after flags (allowed: 1, 2, or 3)", len(remaining))
 	}
 
+	// The text - means read it from stdin
+	if opts.Text == stdinName {
+		opts.Text = ""
+		opts.InputFile = stdinName
+	}
+
+	// Turn escapes like \n and \t into the characters they stand for
+	if err := opts.decodeEscapes(); err != nil {
+		return opts, err
+	}
+
 	return opts, nil
 }
+
+// decodeEscapes decodes the escapes in the text and the substring, unless
+// --no-escapes was given (text read from a file is never decoded, its
+// newlines are real ones already)
+func (opts *ColorOptions) decodeEscapes() error {
+	if opts.NoEscapes {
+		return nil
+	}
+	var err error
+	if opts.Text, err = DecodeEscapes(opts.Text); err != nil {
+		return fmt.Errorf("in the text: %w; write \\\\ for a backslash, or use --no-escapes", err)
+	}
+	if opts.Substring, err = DecodeEscapes(opts.Substring); err != nil {
+		return fmt.Errorf("in the substring: %w; write \\\\ for a backslash, or use --no-escapes", err)
+	}
+	return nil
+}
+
+// parseInputArgs reads the arguments left after the flags when the text
+// comes from --input or stdin: [banner], [substring] or [substring, banner]
+func parseInputArgs(opts ColorOptions, remaining []string, registry *BannerRegistry) (ColorOptions, error) {
+	switch len(remaining) {
+	case 0:
+		// Just the text from the file
+
+	case 1:
+		// Could be: [banner] OR [substring]
+		if registry.Has(remaining[0]) {
+			opts.Banner = remaining[0]
+		} else if opts.UseColor {
+			opts.SubstringArgProvided = true
+			opts.Substring = remaining[0]
+		} else {
+			return opts, fmt.Errorf("invalid arguments: %q is not a banner (%s); the text comes from %s", remaining[0], strings.Join(registry.Names(), "/"), opts.inputName())
+		}
+
+	case 2:
+		// [substring, banner] - only valid with color flag
+		if !opts.UseColor {
+			return opts, fmt.Errorf("too many arguments (%d) without --color (max 1: banner, the text comes from %s)", len(remaining), opts.inputName())
+		}
+		opts.SubstringArgProvided = true
+		opts.Substring = remaining[0]
+		opts.Banner = remaining[1]
+
+	default:
+		return opts, fmt.Errorf("too many arguments: got %d after flags (allowed: 0, 1, or 2 when the text comes from %s)", len(remaining), opts.inputName())
+	}
+
+	return opts, nil
+}
+
+// inputName says where the text is read from, for messages
+func (opts ColorOptions) inputName() string {
+	if opts.InputFile == stdinName {
+		return "stdin"
+	}
+	return opts.InputFile
+}
@@ -72,6 +72,16 @@ func TestFindSubstringIndexes_EmptySubstring(t *testing.T) {
 	}
 }
 
+// Test that positions count characters, not bytes
+func TestFindSubstringIndexes_Unicode(t *testing.T) {
+	indexes := FindSubstringIndexes("Καλή μέρα", "μέ")
+	expected := []int{5, 6}
+
+	if !equalSlices(indexes, expected) {
+		t.Errorf("Expected %v, got %v", expected, indexes)
+	}
+}
+
 // Test basic argument parsing without color
 func TestParseColorArgs_NoColor(t *testing.T) {
 	args := []string{"program", "Hello"}
@@ -0,0 +1,386 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"unicode/utf8"
+)
+
+// command is a subcommand like "validate", run as: ascii-art validate <banner>
+// it gets the arguments after its name and returns the exit status
+type command func(args []string, stdout, stderr io.Writer) int
+
+// commands lists every subcommand by name
+var commands = map[string]command{
+	"validate":    runValidate,
+	"import-bdf":  runImportBDF,
+	"export":      runExport,
+	"banners":     runBanners,
+	"banner-diff": runBannerDiff,
+	"inspect":     runInspect,
+}
+
+// Usage lines for each subcommand
+const (
+	validateUsage  = "validate [--banner-dir=<dir>] <banner>..."
+	importBDFUsage = "import-bdf [--ink=<char>] <font.bdf> [<banner.txt>]"
+	exportUsage    = "export [--banner-dir=<dir>] [--format=flf|txt] <banner> [<file>]"
+	bannersUsage   = "banners [--banner-dir=<dir>] list | info <banner>..."
+	diffUsage      = "banner-diff [--banner-dir=<dir>] [--no-color] <old> <new>"
+	inspectUsage   = "inspect [--banner-dir=<dir>] <banner> <characters>"
+)
+
+// bannerTextCommands are the subcommands whose names are still rendered as
+// text when a banner name is all that follows, as they were before the
+// subcommands existed (e.g. `go run . banners shadow`)
+var bannerTextCommands = map[string]bool{
+	"banners": true,
+	"export":  true,
+	"inspect": true,
+}
+
+// runCommand checks whether the arguments start with a subcommand and runs it
+// a subcommand name on its own (e.g. `go run . validate`) is still rendered
+// as text, so only "<name> <something>" counts as a command; to render
+// "validate standard" as text, start with -- (`go run . -- validate standard`)
+func runCommand(args []string, stdout, stderr io.Writer) (bool, int) {
+	if len(args) < 3 {
+		return false, 0
+	}
+	cmd, ok := commands[args[1]]
+	if !ok {
+		return false, 0
+	}
+	if len(args) == 3 && bannerTextCommands[args[1]] && NewBannerRegistry(nil).Has(args[2]) {
+		return false, 0
+	}
+	return true, cmd(args[2:], stdout, stderr)
+}
+
+// splitBannerDirs pulls the --banner-dir= flags out of a command's arguments
+func splitBannerDirs(args []string) (dirs []string, rest []string, err error) {
+	for _, arg := range args {
+		if strings.HasPrefix(arg, "--banner-dir=") {
+			dir := arg[13:] // After "--banner-dir="
+			if dir == "" {
+				return nil, nil, fmt.Errorf("empty banner directory")
+			}
+			dirs = append(dirs, dir)
+			continue
+		}
+		rest = append(rest, arg)
+	}
+	return dirs, rest, nil
+}
+
+// runValidate checks each banner and prints every problem found
+// exit status: 0 if all banners are fine, 1 if any has problems, 2 for bad usage
+func runValidate(args []string, stdout, stderr io.Writer) int {
+	dirs, names, err := splitBannerDirs(args)
+	if err != nil || len(names) == 0 {
+		if err != nil {
+			fmt.Fprintf(stderr, "Error: %v\n", err)
+		}
+		fmt.Fprintln(stderr, "Usage: go run . "+validateUsage)
+		return 2
+	}
+
+	registry := NewBannerRegistry(dirs)
+	status := 0
+
+	for _, name := range names {
+		data, where, err := registry.ReadFile(name)
+		if err != nil {
+			fmt.Fprintf(stderr, "Error: %s: %v\n", name, err)
+			status = 1
+			continue
+		}
+
+		// FIGlet and BDF fonts have their own formats, we can only check they load
+		var issues []BannerIssue
+		if isFIGletPath(where) {
+			if _, err := parseFIGlet(string(data)); err != nil {
+				issues = append(issues, BannerIssue{Line: 1, Message: err.Error()})
+			}
+		} else if isBDFPath(where) {
+			if _, _, err := parseBDFInfo(string(data), defaultInk); err != nil {
+				issues = append(issues, BannerIssue{Line: 1, Message: err.Error()})
+			}
+		} else {
+			issues = validateBannerData(data)
+		}
+
+		for _, issue := range issues {
+			fmt.Fprintf(stdout, "%s:%s\n", where, strings.TrimPrefix(issue.String(), "line "))
+		}
+		if len(issues) > 0 {
+			fmt.Fprintf(stdout, "%s: %d problem(s)\n", where, len(issues))
+			status = 1
+		} else {
+			fmt.Fprintf(stdout, "%s: ok\n", where)
+		}
+	}
+
+	return status
+}
+
+// runImportBDF converts a BDF bitmap font into a banner file
+// (written to the given file, or printed if there is none)
+func runImportBDF(args []string, stdout, stderr io.Writer) int {
+	ink := defaultInk
+	var files []string
+	for _, arg := range args {
+		if strings.HasPrefix(arg, "--ink=") {
+			value := arg[6:] // After "--ink="
+			if utf8.RuneCountInString(value) != 1 {
+				fmt.Fprintf(stderr, "Error: --ink needs exactly one character, got %q\n", value)
+				return 2
+			}
+			ink, _ = utf8.DecodeRuneInString(value)
+			continue
+		}
+		files = append(files, arg)
+	}
+	if len(files) < 1 || len(files) > 2 {
+		fmt.Fprintln(stderr, "Usage: go run . "+importBDFUsage)
+		return 2
+	}
+
+	data, err := os.ReadFile(files[0])
+	if err != nil {
+		fmt.Fprintf(stderr, "Error: %v\n", err)
+		return 1
+	}
+	banner, info, err := parseBDFInfo(string(data), ink)
+	if err != nil {
+		fmt.Fprintf(stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	if len(files) == 1 {
+		if err := WriteBannerInfo(stdout, banner, info); err != nil {
+			fmt.Fprintf(stderr, "Error: %v\n", err)
+			return 1
+		}
+		return 0
+	}
+
+	file, err := os.Create(files[1])
+	if err != nil {
+		fmt.Fprintf(stderr, "Error: %v\n", err)
+		return 1
+	}
+	err = WriteBannerInfo(file, banner, info)
+	// a failed close can mean the banner never fully reached the disk
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		fmt.Fprintf(stderr, "Error writing banner: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+// runExport writes a banner out as a FIGlet font (flf) or a banner file (txt)
+// the format comes from --format, else the output file's extension, else flf
+func runExport(args []string, stdout, stderr io.Writer) int {
+	dirs, rest, err := splitBannerDirs(args)
+	if err != nil {
+		fmt.Fprintf(stderr, "Error: %v\n", err)
+		return 2
+	}
+
+	format := ""
+	var files []string
+	for _, arg := range rest {
+		if strings.HasPrefix(arg, "--format=") {
+			format = arg[9:] // After "--format="
+			continue
+		}
+		files = append(files, arg)
+	}
+	if len(files) < 1 || len(files) > 2 {
+		fmt.Fprintln(stderr, "Usage: go run . "+exportUsage)
+		return 2
+	}
+	if format == "" {
+		format = "flf"
+		if len(files) == 2 && strings.HasSuffix(files[1], ".txt") {
+			format = "txt"
+		}
+	}
+	if format != "flf" && format != "txt" {
+		fmt.Fprintf(stderr, "Error: unknown format %q (expected flf or txt)\n", format)
+		return 2
+	}
+
+	name := files[0]
+	banner, info, err := NewBannerRegistry(dirs).LoadInfo(name)
+	if err != nil {
+		fmt.Fprintf(stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	var out io.Writer = stdout
+	var file *os.File
+	if len(files) == 2 {
+		file, err = os.Create(files[1])
+		if err != nil {
+			fmt.Fprintf(stderr, "Error: %v\n", err)
+			return 1
+		}
+		out = file
+	}
+
+	if format == "flf" {
+		err = WriteFIGlet(out, banner, info)
+	} else {
+		err = WriteBannerInfo(out, banner, info)
+	}
+	// a failed close can mean the font never fully reached the disk
+	if file != nil {
+		if closeErr := file.Close(); err == nil {
+			err = closeErr
+		}
+	}
+	if err != nil {
+		fmt.Fprintf(stderr, "Error writing %s: %v\n", format, err)
+		return 1
+	}
+	return 0
+}
+
+// runBanners lists the banners in the search path, or shows the
+// info (header) of the given banners
+func runBanners(args []string, stdout, stderr io.Writer) int {
+	dirs, rest, err := splitBannerDirs(args)
+	switch {
+	case err == nil && len(rest) == 1 && rest[0] == "list":
+	case err == nil && len(rest) > 1 && rest[0] == "info":
+	default:
+		fmt.Fprintln(stderr, "Usage: go run . "+bannersUsage)
+		return 2
+	}
+
+	registry := NewBannerRegistry(dirs)
+	if rest[0] == "list" {
+		for _, name := range registry.Names() {
+			fmt.Fprintln(stdout, name)
+		}
+		return 0
+	}
+
+	status := 0
+	for i, name := range rest[1:] {
+		banner, info, err := registry.LoadInfo(name)
+		if err != nil {
+			fmt.Fprintf(stderr, "Error: %v\n", err)
+			status = 1
+			continue
+		}
+		_, where, _ := registry.ReadFile(name)
+
+		if i > 0 {
+			fmt.Fprintln(stdout)
+		}
+		// fields that aren't set show what is used instead
+		orElse := func(value, fallback string) string {
+			if value == "" {
+				return fallback
+			}
+			return value
+		}
+		fmt.Fprintf(stdout, "name:       %s\n", info.Name)
+		fmt.Fprintf(stdout, "file:       %s\n", where)
+		fmt.Fprintf(stdout, "author:     %s\n", orElse(info.Author, "(unknown)"))
+		fmt.Fprintf(stdout, "license:    %s\n", orElse(info.License, "(unknown)"))
+		fmt.Fprintf(stdout, "height:     %d\n", banner.Height())
+		fmt.Fprintf(stdout, "baseline:   %s\n", orElse(info.get("baseline"), "(unknown)"))
+		fmt.Fprintf(stdout, "ink:        %s\n", orElse(info.Ink, "(anything but spaces)"))
+		fmt.Fprintf(stdout, "layout:     %s\n", orElse(info.Layout, "full"))
+		fmt.Fprintf(stdout, "characters: %d\n", len(banner))
+	}
+	return status
+}
+
+// runBannerDiff compares two banners (names or files)
+// exit status: 0 if they are the same, 1 if they differ, 2 for bad usage
+func runBannerDiff(args []string, stdout, stderr io.Writer) int {
+	dirs, rest, err := splitBannerDirs(args)
+	highlight := true
+	var names []string
+	for _, arg := range rest {
+		if arg == "--no-color" {
+			highlight = false
+			continue
+		}
+		names = append(names, arg)
+	}
+	if err != nil || len(names) != 2 {
+		fmt.Fprintln(stderr, "Usage: go run . "+diffUsage)
+		return 2
+	}
+
+	registry := NewBannerRegistry(dirs)
+	var banners [2]Banner
+	for i, name := range names {
+		banners[i], err = registry.Load(name)
+		if err != nil {
+			fmt.Fprintf(stderr, "Error: %v\n", err)
+			return 2
+		}
+	}
+
+	diff := DiffBanners(banners[0], banners[1])
+	WriteBannerDiff(stdout, diff, banners[0], banners[1], highlight)
+	if diff.Empty() {
+		return 0
+	}
+	return 1
+}
+
+// runInspect prints the given characters of a banner with rulers, row widths
+// and (for .txt banners) the lines of the file each one comes from
+// exit status: 0 if every character was found, 1 if not, 2 for bad usage
+func runInspect(args []string, stdout, stderr io.Writer) int {
+	dirs, rest, err := splitBannerDirs(args)
+	if err != nil || len(rest) != 2 {
+		fmt.Fprintln(stderr, "Usage: go run . "+inspectUsage)
+		return 2
+	}
+	name, chars := rest[0], rest[1]
+
+	registry := NewBannerRegistry(dirs)
+	banner, err := registry.Load(name)
+	if err != nil {
+		fmt.Fprintf(stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	// line numbers are only known for our own banner format
+	firstLines := make(map[rune]int)
+	data, where, _ := registry.ReadFile(name)
+	if !isFIGletPath(where) && !isBDFPath(where) {
+		blocks, _, _ := parseBannerBlocks(data)
+		for _, block := range blocks {
+			firstLines[block.char] = block.line + 1 // the row after the separator
+		}
+	}
+
+	fmt.Fprintf(stdout, "%s (height %d)\n", where, banner.Height())
+	status := 0
+	for _, ch := range chars {
+		fmt.Fprintln(stdout)
+		glyph, ok := banner[ch]
+		if !ok {
+			fmt.Fprintf(stdout, "%q (U+%04X): not in this banner\n", ch, ch)
+			status = 1
+			continue
+		}
+		WriteGlyphInspection(stdout, ch, glyph, firstLines[ch])
+	}
+	return status
+}
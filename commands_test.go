@@ -0,0 +1,113 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// test that a subcommand name on its own is still treated as text
+func TestRunCommandNotACommand(t *testing.T) {
+	var out, errOut bytes.Buffer
+	handled, _ := runCommand([]string{"program", "validate"}, &out, &errOut)
+	if handled {
+		t.Error("\"validate\" alone should be rendered as text")
+	}
+}
+
+// test that a subcommand name followed by more arguments runs the command,
+// and that -- in front makes it text again
+func TestRunCommandOrText(t *testing.T) {
+	var out, errOut bytes.Buffer
+	if handled, _ := runCommand([]string{"program", "validate", "standard"}, &out, &errOut); !handled {
+		t.Error("\"validate standard\" should run the validate command")
+	}
+
+	// banners, export and inspect followed by just a banner are still text
+	for _, args := range [][]string{
+		{"program", "banners", "shadow"},
+		{"program", "export", "thinkertoy"},
+		{"program", "inspect", "standard"},
+	} {
+		if handled, _ := runCommand(args, &out, &errOut); handled {
+			t.Errorf("%v should be rendered as text", args[1:])
+		}
+	}
+	if handled, _ := runCommand([]string{"program", "banners", "list"}, &out, &errOut); !handled {
+		t.Error("\"banners list\" should run the banners command")
+	}
+	if handled, _ := runCommand([]string{"program", "export", "--format=flf", "thinkertoy"}, &out, &errOut); !handled {
+		t.Error("\"export --format=flf thinkertoy\" should run the export command")
+	}
+
+	args := []string{"program", "--", "validate", "standard"}
+	if handled, _ := runCommand(args, &out, &errOut); handled {
+		t.Error("\"-- validate standard\" should be rendered as text")
+	}
+	opts, err := ParseColorArgs(args)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if opts.Text != "validate" || opts.Banner != "standard" {
+		t.Errorf("expected text \"validate\" in standard, got %q in %q", opts.Text, opts.Banner)
+	}
+
+	// text starting with -- after the options
+	opts, err = ParseColorArgs([]string{"program", "--color=red", "--", "--Hi"})
+	if err != nil || opts.Text != "--Hi" || !opts.UseColor {
+		t.Errorf("expected colored text \"--Hi\", got %q (%v)", opts.Text, err)
+	}
+}
+
+// test that validate loads BDF fonts as BDF, not as banner files
+func TestRunValidateBDF(t *testing.T) {
+	dir := t.TempDir()
+	good := filepath.Join(dir, "ok.bdf")
+	bad := filepath.Join(dir, "bad.bdf")
+	if err := os.WriteFile(good, []byte(testBDF), 0644); err != nil {
+		t.Fatalf("failed to write font: %v", err)
+	}
+	broken := strings.Replace(testBDF, "DWIDTH 6 0", "DWIDTH -9 0", 1)
+	if err := os.WriteFile(bad, []byte(broken), 0644); err != nil {
+		t.Fatalf("failed to write font: %v", err)
+	}
+
+	var out, errOut bytes.Buffer
+	if status := runValidate([]string{good}, &out, &errOut); status != 0 {
+		t.Errorf("expected status 0 for a good BDF font, got %d: %s", status, out.String())
+	}
+
+	out.Reset()
+	if status := runValidate([]string{bad}, &out, &errOut); status != 1 {
+		t.Errorf("expected status 1 for a broken BDF font, got %d", status)
+	}
+	if !strings.Contains(out.String(), "negative DWIDTH") {
+		t.Errorf("expected the load error in the report, got:\n%s", out.String())
+	}
+}
+
+// test the validate command's exit status
+func TestRunValidate(t *testing.T) {
+	var out, errOut bytes.Buffer
+	if status := runValidate([]string{"standard"}, &out, &errOut); status != 0 {
+		t.Errorf("expected status 0 for standard, got %d: %s", status, out.String())
+	}
+
+	// a banner with one row too wide
+	path := filepath.Join(t.TempDir(), "broken.txt")
+	data, _ := builtinBanners.ReadFile("banners/standard.txt")
+	broken := strings.Replace(string(data), " _  \n", " _   \n", 1)
+	if err := os.WriteFile(path, []byte(broken), 0644); err != nil {
+		t.Fatalf("failed to write banner: %v", err)
+	}
+
+	out.Reset()
+	if status := runValidate([]string{path}, &out, &errOut); status != 1 {
+		t.Errorf("expected status 1 for broken banner, got %d", status)
+	}
+	if !strings.Contains(out.String(), "broken.txt:11:") {
+		t.Errorf("expected a line number in the report, got:\n%s", out.String())
+	}
+}
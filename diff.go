@@ -0,0 +1,100 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"unicode/utf8"
+)
+
+// BannerDiff lists which characters differ between two versions of a banner
+type BannerDiff struct {
+	Added   []rune // only in the new banner
+	Removed []rune // only in the old banner
+	Changed []rune // in both, but drawn differently
+}
+
+// Empty reports whether the two banners had no differences
+func (d BannerDiff) Empty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Changed) == 0
+}
+
+// DiffBanners compares two banners character by character
+// each list is sorted by character code
+func DiffBanners(old, new Banner) BannerDiff {
+	var diff BannerDiff
+	for ch, oldGlyph := range old {
+		newGlyph, ok := new[ch]
+		if !ok {
+			diff.Removed = append(diff.Removed, ch)
+		} else if !slices.Equal(oldGlyph, newGlyph) {
+			diff.Changed = append(diff.Changed, ch)
+		}
+	}
+	for ch := range new {
+		if _, ok := old[ch]; !ok {
+			diff.Added = append(diff.Added, ch)
+		}
+	}
+	slices.Sort(diff.Added)
+	slices.Sort(diff.Removed)
+	slices.Sort(diff.Changed)
+	return diff
+}
+
+// formatRunes writes a list of characters like 'A' 'g' 'Ω', or "none"
+func formatRunes(chars []rune) string {
+	if len(chars) == 0 {
+		return "none"
+	}
+	parts := make([]string, len(chars))
+	for i, ch := range chars {
+		parts[i] = fmt.Sprintf("%q", ch)
+	}
+	return strings.Join(parts, " ")
+}
+
+// WriteBannerDiff prints a diff: the added, removed and changed characters,
+// then every changed glyph old | new side by side
+// rows that differ are marked with * and, when highlight is true,
+// drawn red (old) and green (new)
+func WriteBannerDiff(w io.Writer, diff BannerDiff, old, new Banner, highlight bool) {
+	fmt.Fprintf(w, "added:   %s\n", formatRunes(diff.Added))
+	fmt.Fprintf(w, "removed: %s\n", formatRunes(diff.Removed))
+	fmt.Fprintf(w, "changed: %s\n", formatRunes(diff.Changed))
+
+	red, _ := GetColorCode("red")
+	green, _ := GetColorCode("green")
+
+	for _, ch := range diff.Changed {
+		oldGlyph, newGlyph := old[ch], new[ch]
+		width := glyphWidth(oldGlyph)
+		rows := max(len(oldGlyph), len(newGlyph))
+
+		fmt.Fprintf(w, "\n%q\n", ch)
+		for row := 0; row < rows; row++ {
+			oldRow, newRow := glyphRowText(oldGlyph, row), glyphRowText(newGlyph, row)
+			padding := strings.Repeat(" ", width-utf8.RuneCountInString(oldRow))
+
+			marker := " "
+			if row >= len(oldGlyph) || row >= len(newGlyph) || oldGlyph[row] != newGlyph[row] {
+				marker = "*"
+				if highlight {
+					oldRow = red + oldRow + ResetColor
+					newRow = green + newRow + ResetColor
+				}
+			}
+			fmt.Fprintf(w, "%s %2d %s%s | %s\n", marker, row+1, oldRow, padding, newRow)
+		}
+	}
+}
+
+// glyphRowText returns one row of a glyph ready to print
+// ("" past the last row, hardblanks shown as spaces)
+func glyphRowText(glyph []string, row int) string {
+	if row >= len(glyph) {
+		return ""
+	}
+	return strings.ReplaceAll(glyph[row], string(hardblank), " ")
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// test that added, removed and changed characters are found
+func TestDiffBanners(t *testing.T) {
+	old := Banner{
+		'A': {"/\\", "||"},
+		'B': {"|)", "|)"},
+		'C': {"/ ", "\\ "},
+	}
+	new := Banner{
+		'A': {"/\\", "||"},
+		'B': {"|>", "|)"},
+		'D': {"|\\", "|/"},
+	}
+
+	diff := DiffBanners(old, new)
+	if !slices.Equal(diff.Added, []rune{'D'}) {
+		t.Errorf("expected D added, got %q", diff.Added)
+	}
+	if !slices.Equal(diff.Removed, []rune{'C'}) {
+		t.Errorf("expected C removed, got %q", diff.Removed)
+	}
+	if !slices.Equal(diff.Changed, []rune{'B'}) {
+		t.Errorf("expected B changed, got %q", diff.Changed)
+	}
+	if !DiffBanners(old, old).Empty() {
+		t.Error("a banner should have no differences with itself")
+	}
+}
+
+// test that only the rows that differ are marked and colored
+func TestWriteBannerDiff(t *testing.T) {
+	old := Banner{'B': {"|)", "|)"}}
+	new := Banner{'B': {"|>", "|)"}}
+
+	var out bytes.Buffer
+	WriteBannerDiff(&out, DiffBanners(old, new), old, new, true)
+	text := out.String()
+
+	if !strings.Contains(text, "changed: 'B'") {
+		t.Errorf("expected B in the changed list, got:\n%s", text)
+	}
+	if !strings.Contains(text, "*  1 \033[31m|)\033[0m | \033[32m|>\033[0m\n") {
+		t.Errorf("expected the first row marked and colored, got:\n%q", text)
+	}
+	if !strings.Contains(text, "   2 |) | |)\n") {
+		t.Errorf("expected the second row unmarked, got:\n%q", text)
+	}
+}
+
+// test the banner-diff command's exit status
+func TestRunBannerDiff(t *testing.T) {
+	var out, errOut bytes.Buffer
+	if status := runBannerDiff([]string{"standard", "standard"}, &out, &errOut); status != 0 {
+		t.Errorf("expected status 0 for the same banner, got %d", status)
+	}
+
+	out.Reset()
+	if status := runBannerDiff([]string{"standard", "shadow"}, &out, &errOut); status != 1 {
+		t.Errorf("expected status 1 for different banners, got %d", status)
+	}
+
+	// a copy of standard with an extra character added at the end
+	path := filepath.Join(t.TempDir(), "copy.txt")
+	data, _ := builtinBanners.ReadFile("banners/standard.txt")
+	extra := "U+00E9\n" + strings.Repeat(" e \n", 8)
+	if err := os.WriteFile(path, append(data, extra...), 0644); err != nil {
+		t.Fatalf("failed to write banner: %v", err)
+	}
+	out.Reset()
+	runBannerDiff([]string{"--no-color", "standard", path}, &out, &errOut)
+	if !strings.Contains(out.String(), "added:   'é'") {
+		t.Errorf("expected é added, got:\n%s", out.String())
+	}
+	out.Reset()
+	runBannerDiff([]string{"--no-color", path, "standard"}, &out, &errOut)
+	if !strings.Contains(out.String(), "removed: 'é'") {
+		t.Errorf("expected é removed, got:\n%s", out.String())
+	}
+
+	if status := runBannerDiff([]string{"standard"}, &out, &errOut); status != 2 {
+		t.Errorf("expected status 2 for bad usage, got %d", status)
+	}
+}
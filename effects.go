@@ -0,0 +1,224 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Wave moves the columns of the art up and down along a sine wave
+// Amplitude is how many rows up or down they go, Period how many columns
+// one whole wave takes; the zero value leaves the art flat
+type Wave struct {
+	Amplitude int
+	Period    int
+}
+
+// defaultWavePeriod is used when --wave only gives the amplitude
+const defaultWavePeriod = 16
+
+// maxWaveAmplitude and maxShear limit how far the effects move the art,
+// as every row or column moved adds blank cells to the block
+const (
+	maxWaveAmplitude = 20
+	maxShear         = 10
+)
+
+// ParseWave reads a --wave value: "2" for an amplitude of 2 rows, or
+// "2,12" for an amplitude of 2 rows and a period of 12 columns
+func ParseWave(value string) (Wave, error) {
+	amplitude, period, found := strings.Cut(value, ",")
+	if !found {
+		period = strconv.Itoa(defaultWavePeriod)
+	}
+	a, errA := strconv.Atoi(amplitude)
+	p, errP := strconv.Atoi(period)
+	if errA != nil || errP != nil || a < 0 || a > maxWaveAmplitude || p < 2 {
+		return Wave{}, fmt.Errorf("invalid wave %q (expected <amplitude> or <amplitude>,<period>, amplitude 0 to %d, period at least 2)", value, maxWaveAmplitude)
+	}
+	return Wave{Amplitude: a, Period: p}, nil
+}
+
+// Shadow draws a copy of the ink behind the art, DX columns right and DY
+// rows down (negative goes left/up); with Extrude the copies fill the
+// whole way from the art to the offset, for a 3D look
+// the zero value draws no shadow
+type Shadow struct {
+	DX, DY  int
+	Extrude bool
+	Char    rune   // the shadow is drawn with this, defaultShadowChar if 0
+	Color   string // ANSI color code for the shadow, "" for none
+}
+
+// defaultShadowChar is what shadows are drawn with unless --shadow-char says
+const defaultShadowChar = '░'
+
+// maxShadowOffset is the furthest a shadow can be from the art either way,
+// as the block grows by the offset
+const maxShadowOffset = 20
+
+// ParseShadowOffset reads a --shadow or --extrude value: "dx,dy" like "2,1"
+func ParseShadowOffset(value string) (int, int, error) {
+	xs, ys, found := strings.Cut(value, ",")
+	dx, errX := strconv.Atoi(xs)
+	dy, errY := strconv.Atoi(ys)
+	if !found || errX != nil || errY != nil || (dx == 0 && dy == 0) ||
+		dx < -maxShadowOffset || dx > maxShadowOffset || dy < -maxShadowOffset || dy > maxShadowOffset {
+		return 0, 0, fmt.Errorf("invalid shadow offset %q (expected dx,dy like 2,1, each from -%d to %d)", value, maxShadowOffset, maxShadowOffset)
+	}
+	return dx, dy, nil
+}
+
+// ParseShear reads a --shear value: how many columns each row is moved
+// right of the row below it (negative leans the other way)
+func ParseShear(value string) (int, error) {
+	shear, err := strconv.Atoi(value)
+	if err != nil || shear < -maxShear || shear > maxShear {
+		return 0, fmt.Errorf("invalid shear %q (expected a whole number from -%d to %d, like 1 or -1)", value, maxShear, maxShear)
+	}
+	return shear, nil
+}
+
+// distortedWidth is how wide a block of width columns and height rows is
+// once distort has been applied (every effect pads the block first, then
+// shear and the shadow make it wider)
+func (opts RenderOptions) distortedWidth(width, height int) int {
+	if opts.Shear != 0 {
+		width += (height - 1) * abs(opts.Shear)
+	}
+	if opts.Shadow.DX != 0 || opts.Shadow.DY != 0 {
+		width += abs(opts.Shadow.DX)
+	}
+	return width
+}
+
+// distort applies the shear and the wave to one rendered line of text
+// (before the lines are put under each other, so each line leans and
+// waves by itself); cells move with their colors
+func (opts RenderOptions) distort(g grid) grid {
+	if opts.Shear != 0 {
+		g = shearGrid(g, opts.Shear)
+	}
+	if opts.Wave.Amplitude > 0 && opts.Wave.Period > 1 {
+		g = waveGrid(g, opts.Wave)
+	}
+	if opts.Shadow.DX != 0 || opts.Shadow.DY != 0 {
+		g = shadowGrid(g, opts.Shadow)
+	}
+	return g
+}
+
+// shearGrid fakes italics: the bottom row stays put and every row above
+// it is moved shear more columns to the right (left if shear is negative)
+// all rows are padded to the same width so the block stays square
+func shearGrid(g grid, shear int) grid {
+	g = g.padded()
+	last := len(g) - 1
+	maxShift := last * abs(shear)
+
+	out := make(grid, len(g))
+	for r, row := range g {
+		shift := (last - r) * shear
+		if shear < 0 {
+			shift = r * -shear
+		}
+		out[r] = append(out[r], blankCells(shift)...)
+		out[r] = append(out[r], row...)
+		out[r] = append(out[r], blankCells(maxShift-shift)...)
+	}
+	return out
+}
+
+// waveGrid moves each column up or down by amplitude * sin(column / period)
+// the block gets amplitude extra rows on the top and the bottom for room
+func waveGrid(g grid, w Wave) grid {
+	g = g.padded()
+	height := len(g) + 2*w.Amplitude
+
+	out := make(grid, height)
+	width := 0
+	if len(g) > 0 {
+		width = len(g[0])
+	}
+	for r := range out {
+		out[r] = blankCells(width)
+	}
+	for col := 0; col < width; col++ {
+		angle := 2 * math.Pi * float64(col) / float64(w.Period)
+		offset := int(math.Round(float64(w.Amplitude) * math.Sin(angle)))
+		for r, row := range g {
+			out[r+w.Amplitude-offset][col] = row[col]
+		}
+	}
+	return out
+}
+
+// shadowGrid draws the shadow behind the ink of a grid; the block grows by
+// the offset so the shadow fits, and the shadow only shows where the art
+// itself has no ink
+func shadowGrid(g grid, s Shadow) grid {
+	g = g.padded()
+	if len(g) == 0 {
+		return g
+	}
+	char := s.Char
+	if char == 0 {
+		char = defaultShadowChar
+	}
+	shadow := cell{ch: char, color: s.Color, owner: -1}
+
+	// a negative offset means the art moves right/down to make room
+	height, width := len(g), len(g[0])
+	top, left := max(0, -s.DY), max(0, -s.DX)
+	out := make(grid, height+abs(s.DY))
+	for r := range out {
+		out[r] = blankCells(width + abs(s.DX))
+	}
+	isInk := make([][]bool, len(out))
+	for r := range out {
+		isInk[r] = make([]bool, len(out[r]))
+	}
+	for r, row := range g {
+		for c, cl := range row {
+			out[top+r][left+c] = cl
+			isInk[top+r][left+c] = cl.ch != ' ' && cl.ch != hardblank
+		}
+	}
+
+	// a shadow is one copy at the offset, an extrusion one copy per step
+	steps := 1
+	if s.Extrude {
+		steps = max(abs(s.DX), abs(s.DY))
+	}
+	for step := 1; step <= steps; step++ {
+		dx := int(math.Round(float64(s.DX*step) / float64(steps)))
+		dy := int(math.Round(float64(s.DY*step) / float64(steps)))
+		for r, row := range g {
+			for c := range row {
+				tr, tc := top+r+dy, left+c+dx
+				if isInk[top+r][left+c] && !isInk[tr][tc] {
+					out[tr][tc] = shadow
+				}
+			}
+		}
+	}
+	return out
+}
+
+// blankCells returns n uncolored spaces
+func blankCells(n int) []cell {
+	cells := make([]cell, n)
+	for i := range cells {
+		cells[i] = cell{ch: ' '}
+	}
+	return cells
+}
+
+// abs returns the size of n without its sign
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
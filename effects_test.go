@@ -0,0 +1,117 @@
+package main
+
+import "testing"
+
+// test reading --shear and --wave values
+func TestParseShearAndWave(t *testing.T) {
+	if shear, err := ParseShear("-2"); err != nil || shear != -2 {
+		t.Errorf("expected -2, got %d (%v)", shear, err)
+	}
+	if _, err := ParseShear("lots"); err == nil {
+		t.Error("expected an error for a shear that isn't a number")
+	}
+	for _, big := range []string{"11", "-11", "1000000", "-9223372036854775808"} {
+		if _, err := ParseShear(big); err == nil {
+			t.Errorf("%q: expected an error for a shear over %d", big, maxShear)
+		}
+	}
+
+	if wave, err := ParseWave("2"); err != nil || wave != (Wave{2, defaultWavePeriod}) {
+		t.Errorf("expected amplitude 2 with the default period, got %v (%v)", wave, err)
+	}
+	if wave, err := ParseWave("1,8"); err != nil || wave != (Wave{1, 8}) {
+		t.Errorf("expected {1 8}, got %v (%v)", wave, err)
+	}
+	for _, bad := range []string{"", "-1", "1,1", "1,x", "big", "21", "1000000,8"} {
+		if _, err := ParseWave(bad); err == nil {
+			t.Errorf("%q: expected an error", bad)
+		}
+	}
+}
+
+// test that shear leans the rows and keeps the block square
+func TestRenderShear(t *testing.T) {
+	b := Banner{'l': {"|", "|", "|"}}
+	tests := []struct {
+		shear int
+		want  string
+	}{
+		{1, "  |\n | \n|  \n"},
+		{-1, "|  \n | \n  |\n"},
+	}
+	for _, tt := range tests {
+		got := RenderInputOptions("l", b, RenderOptions{Shear: tt.shear})
+		if got != tt.want {
+			t.Errorf("shear %d: expected %q, got %q", tt.shear, tt.want, got)
+		}
+	}
+}
+
+// test that the wave moves columns and the colors move with them
+func TestRenderWaveColor(t *testing.T) {
+	b := Banner{'-': {"-"}}
+	opts := RenderOptions{Wave: Wave{Amplitude: 1, Period: 4}}
+
+	// columns 0..3 move by sin: 0, up 1, 0, down 1
+	got := RenderWithColorOptions("----", b, "\033[31m", []int{1}, opts)
+	want := " \033[31m-\033[0m  \n- - \n   -\n"
+	if got != want {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+}
+
+// test reading --shadow offsets
+func TestParseShadowOffset(t *testing.T) {
+	if dx, dy, err := ParseShadowOffset("2,-1"); err != nil || dx != 2 || dy != -1 {
+		t.Errorf("expected 2,-1, got %d,%d (%v)", dx, dy, err)
+	}
+	for _, bad := range []string{"", "2", "0,0", "a,b", "21,0", "0,-21", "1000000,1"} {
+		if _, _, err := ParseShadowOffset(bad); err == nil {
+			t.Errorf("%q: expected an error", bad)
+		}
+	}
+}
+
+// test that the shadow's character and color need a shadow to go with
+func TestParseColorArgs_ShadowFlags(t *testing.T) {
+	for _, args := range [][]string{
+		{"program", "--shadow-char=#", "Hi"},
+		{"program", "--shadow-color=blue", "Hi"},
+	} {
+		if _, err := ParseColorArgs(args); err == nil {
+			t.Errorf("%v: expected an error without --shadow", args)
+		}
+	}
+	for _, args := range [][]string{
+		{"program", "--shadow-char=#", "--shadow=1,1", "Hi"},
+		{"program", "--extrude=1,1", "--shadow-color=blue", "Hi"},
+	} {
+		if _, err := ParseColorArgs(args); err != nil {
+			t.Errorf("%v: unexpected error: %v", args, err)
+		}
+	}
+}
+
+// test that the shadow is drawn behind the ink, in its own color
+func TestRenderShadow(t *testing.T) {
+	b := Banner{'o': {"##", "##"}}
+
+	opts := RenderOptions{Shadow: Shadow{DX: 1, DY: 1, Char: '.'}}
+	want := "## \n##.\n ..\n"
+	if got := RenderInputOptions("o", b, opts); got != want {
+		t.Errorf("shadow: expected %q, got %q", want, got)
+	}
+
+	opts.Shadow.Extrude = true
+	opts.Shadow.DX, opts.Shadow.DY = 2, 0
+	want = "##..\n##..\n"
+	if got := RenderInputOptions("o", b, opts); got != want {
+		t.Errorf("extrude: expected %q, got %q", want, got)
+	}
+
+	opts = RenderOptions{Shadow: Shadow{DX: -1, DY: 0, Char: '.', Color: "\033[34m"}}
+	want = "\033[34m.\033[0m\033[31m##\033[0m\n\033[34m.\033[0m\033[31m##\033[0m\n"
+	if got := RenderWithColorOptions("o", b, "\033[31m", []int{0}, opts); got != want {
+		t.Errorf("colored shadow: expected %q, got %q", want, got)
+	}
+}
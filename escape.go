@@ -0,0 +1,90 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// DecodeEscapes turns the escape sequences typed in the text into the
+// characters they stand for:
+//
+//	\n      a newline (starts a new line of art)
+//	\t      a tab
+//	\\      a backslash
+//	\xHH    the character with code HH (2 hex digits, e.g. \xe9 is é)
+//	\uXXXX  the Unicode character with code XXXX (4 hex digits)
+//
+// a backslash followed by anything else is a mistake: the error says
+// which sequence and where the first one is, and the returned text keeps
+// bad sequences as they were typed
+func DecodeEscapes(s string) (string, error) {
+	runes := []rune(s)
+	var out strings.Builder
+	var firstErr error
+
+	for i := 0; i < len(runes); i++ {
+		if runes[i] != '\\' {
+			out.WriteRune(runes[i])
+			continue
+		}
+
+		ch, n, err := decodeEscape(runes[i:])
+		if err != nil {
+			if firstErr == nil {
+				firstErr = fmt.Errorf("invalid escape %s at character %d (%v)", string(runes[i:i+n]), i+1, err)
+			}
+			out.WriteString(string(runes[i : i+n]))
+		} else {
+			out.WriteRune(ch)
+		}
+		i += n - 1 // skip the rest of the sequence
+	}
+
+	return out.String(), firstErr
+}
+
+// decodeEscape decodes the escape sequence at the start of seq (which
+// starts with a backslash) and says how many characters long it is
+func decodeEscape(seq []rune) (rune, int, error) {
+	if len(seq) < 2 {
+		return 0, 1, fmt.Errorf("nothing after the backslash")
+	}
+	switch seq[1] {
+	case 'n':
+		return '\n', 2, nil
+	case 't':
+		return '\t', 2, nil
+	case '\\':
+		return '\\', 2, nil
+	case 'x':
+		return decodeHexEscape(seq, 2)
+	case 'u':
+		return decodeHexEscape(seq, 4)
+	}
+	return 0, 2, fmt.Errorf(`expected \n, \t, \\, \xHH or \uXXXX`)
+}
+
+// decodeHexEscape decodes \x or \u followed by exactly digits hex digits
+func decodeHexEscape(seq []rune, digits int) (rune, int, error) {
+	n := 2
+	for n < len(seq) && n < 2+digits && isHexDigit(seq[n]) {
+		n++
+	}
+	if n < 2+digits {
+		return 0, n, fmt.Errorf(`\%c needs %d hex digits`, seq[1], digits)
+	}
+
+	code, _ := strconv.ParseUint(string(seq[2:n]), 16, 32)
+	if !utf8.ValidRune(rune(code)) {
+		// e.g. \uD800, half of a UTF-16 surrogate pair
+		return 0, n, fmt.Errorf("U+%04X is not a valid character", code)
+	}
+	return rune(code), n, nil
+}
+
+// isHexDigit reports whether ch is 0-9, a-f or A-F
+func isHexDigit(ch rune) bool {
+	return ('0' <= ch && ch <= '9') || ('a' <= ch && ch <= 'f') || ('A' <= ch && ch <= 'F')
+}
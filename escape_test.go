@@ -0,0 +1,119 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+// test the escape sequences that are understood
+func TestDecodeEscapes(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{`Hello\nWorld`, "Hello\nWorld"},
+		{`a\tb`, "a\tb"},
+		{`a\\nb`, `a\nb`},
+		{`\\\\`, `\\`},
+		{`caf\xe9`, "café"},
+		{`\x41\x42`, "AB"},
+		{`\u00e9t\u00C9`, "étÉ"},
+		{`\u05d0`, "א"},
+		{`no escapes`, "no escapes"},
+		{``, ""},
+	}
+
+	for _, tt := range tests {
+		got, err := DecodeEscapes(tt.input)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", tt.input, err)
+		}
+		if got != tt.want {
+			t.Errorf("%s: expected %q, got %q", tt.input, tt.want, got)
+		}
+	}
+}
+
+// test that bad sequences are reported and kept as typed
+func TestDecodeEscapesErrors(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    string // the decoded text, bad sequences kept
+		message string // part of the error
+	}{
+		{`C:\path`, `C:\path`, `\p at character 3`},
+		{`end\`, `end\`, "nothing after the backslash"},
+		{`\x4`, `\x4`, `\x needs 2 hex digits`},
+		{`\xZZ`, `\xZZ`, `\x needs 2 hex digits`},
+		{`\u12g4`, `\u12g4`, `\u needs 4 hex digits`},
+		{`\uD800`, `\uD800`, "U+D800 is not a valid character"},
+		// only the first bad sequence is in the error, the rest still decode
+		{`\q\n\w`, "\\q\n\\w", `\q at character 1`},
+	}
+
+	for _, tt := range tests {
+		got, err := DecodeEscapes(tt.input)
+		if err == nil {
+			t.Errorf("%s: expected an error", tt.input)
+			continue
+		}
+		if !strings.Contains(err.Error(), tt.message) {
+			t.Errorf("%s: expected the error to say %q, got %q", tt.input, tt.message, err)
+		}
+		if got != tt.want {
+			t.Errorf("%s: expected %q, got %q", tt.input, tt.want, got)
+		}
+	}
+}
+
+// test that the text and substring arguments are decoded, unless
+// --no-escapes is given
+func TestParseColorArgs_Escapes(t *testing.T) {
+	opts, err := ParseColorArgs([]string{"program", "--color=red", `\x6bit`, `a\nkit`})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if opts.Substring != "kit" || opts.Text != "a\nkit" {
+		t.Errorf("expected substring %q and text %q, got %q and %q", "kit", "a\nkit", opts.Substring, opts.Text)
+	}
+	// the positions are in the decoded text, the newline is one character
+	if got := FindSubstringIndexes(opts.Text, opts.Substring); !equalSlices(got, []int{2, 3, 4}) {
+		t.Errorf("expected indexes [2 3 4], got %v", got)
+	}
+
+	opts, err = ParseColorArgs([]string{"program", "--no-escapes", `C:\new`})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if opts.Text != `C:\new` {
+		t.Errorf("expected the text as typed, got %q", opts.Text)
+	}
+
+	if _, err := ParseColorArgs([]string{"program", `C:\path`}); err == nil {
+		t.Error("expected an error for an unknown escape")
+	}
+	if _, err := ParseColorArgs([]string{"program", "--color=red", `\x`, "text"}); err == nil {
+		t.Error("expected an error for a bad escape in the substring")
+	}
+}
+
+// test that a decoded tab is drawn as spaces, as it has no glyph
+func TestRenderTab(t *testing.T) {
+	if got := RenderInput(`a\tb`, wrapBanner); got != "a    b\n" {
+		t.Errorf("expected the tab as %d spaces, got %q", tabWidth, got)
+	}
+
+	banner, err := LoadBanner("banners/standard.txt")
+	if err != nil {
+		t.Fatalf("failed to load banner: %v", err)
+	}
+	if got, want := RenderInput("a\tb", banner), RenderInput("a    b", banner); got != want {
+		t.Errorf("expected the tab drawn like 4 spaces:\n%s\ngot:\n%s", want, got)
+	}
+
+	// the tab is still one character when colors are matched up
+	got := RenderWithColor("a\tb", wrapBanner, "\033[31m", []int{2})
+	if want := "a    \033[31mb\033[0m\n"; got != want {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+}
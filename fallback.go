@@ -0,0 +1,77 @@
+package main
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// WithFallback returns a banner with all of primary's glyphs, plus any
+// character primary is missing borrowed from the fallbacks (first one wins)
+// so e.g. a banner with only uppercase letters can still show "a"
+//
+// borrowed glyphs are resized to primary's height so the lines stay aligned
+func WithFallback(primary Banner, fallbacks ...Banner) Banner {
+	return withFallbackAligned(primary, 0, fallbacks, nil)
+}
+
+// withFallbackAligned is WithFallback for banners whose baselines are known
+// (from their BannerInfo): borrowed glyphs are moved up or down so they sit
+// on primary's baseline; when either baseline is 0 (unknown) the tops line up
+func withFallbackAligned(primary Banner, baseline int, fallbacks []Banner, baselines []int) Banner {
+	height := primary.Height()
+	combined := make(Banner, len(primary))
+
+	for ch, glyph := range primary {
+		combined[ch] = glyph
+	}
+
+	for i, fallback := range fallbacks {
+		shift := 0
+		if i < len(baselines) && baselines[i] != 0 && baseline != 0 {
+			shift = baseline - baselines[i]
+		}
+		for ch, glyph := range fallback {
+			if _, ok := combined[ch]; ok {
+				continue
+			}
+			combined[ch] = shiftGlyph(glyph, height, shift)
+		}
+	}
+
+	return combined
+}
+
+// fitGlyphHeight pads or cuts a glyph so it has exactly height rows
+// extra rows are added (or dropped) at the bottom
+func fitGlyphHeight(rows []string, height int) []string {
+	return shiftGlyph(rows, height, 0)
+}
+
+// shiftGlyph makes a glyph exactly height rows tall, moved down by shift
+// rows (up if negative); rows that move off the top or bottom are dropped,
+// and new rows are spaces as wide as the glyph so the columns after it
+// stay aligned
+func shiftGlyph(rows []string, height, shift int) []string {
+	if shift == 0 && len(rows) == height {
+		return rows
+	}
+	blank := strings.Repeat(" ", glyphWidth(rows))
+	out := make([]string, height)
+	for i := range out {
+		if src := i - shift; src >= 0 && src < len(rows) {
+			out[i] = rows[src]
+		} else {
+			out[i] = blank
+		}
+	}
+	return out
+}
+
+// glyphWidth returns how many characters wide a glyph is (its widest row)
+func glyphWidth(rows []string) int {
+	width := 0
+	for _, row := range rows {
+		width = max(width, utf8.RuneCountInString(row))
+	}
+	return width
+}
@@ -0,0 +1,59 @@
+package main
+
+import "testing"
+
+// test that missing characters are borrowed from the fallback
+func TestWithFallbackBorrowsMissing(t *testing.T) {
+	primary := fakeBanner()
+	fallback := Banner{
+		'A': {"x", "x", "x", "x", "x", "x", "x", "x"},
+		'Z': {"Z0", "Z1", "Z2", "Z3", "Z4", "Z5", "Z6", "Z7"},
+	}
+
+	combined := WithFallback(primary, fallback)
+
+	// A comes from the primary banner, Z is borrowed
+	if combined['A'][0] != "A0" {
+		t.Errorf("expected primary A, got %q", combined['A'])
+	}
+	if combined['Z'][3] != "Z3" {
+		t.Errorf("expected borrowed Z, got %q", combined['Z'])
+	}
+
+	// the primary banner itself is not changed
+	if _, ok := primary['Z']; ok {
+		t.Error("WithFallback should not modify the primary banner")
+	}
+}
+
+// test that the first fallback in the chain wins
+func TestWithFallbackOrder(t *testing.T) {
+	first := Banner{'Z': {"1", "1", "1", "1", "1", "1", "1", "1"}}
+	second := Banner{'Z': {"2", "2", "2", "2", "2", "2", "2", "2"}}
+
+	combined := WithFallback(fakeBanner(), first, second)
+	if combined['Z'][0] != "1" {
+		t.Errorf("expected Z from the first fallback, got %q", combined['Z'])
+	}
+}
+
+// test that borrowed glyphs of a different height are padded or cut
+func TestWithFallbackHeight(t *testing.T) {
+	short := Banner{'Z': {"ZZ", "ZZ", "ZZ"}}
+	tall := Banner{'Y': {"Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y"}}
+
+	combined := WithFallback(fakeBanner(), short, tall)
+
+	if len(combined['Z']) != charHeight || combined['Z'][7] != "  " {
+		t.Errorf("expected Z padded to %d rows of width 2, got %q", charHeight, combined['Z'])
+	}
+	if len(combined['Y']) != charHeight {
+		t.Errorf("expected Y cut to %d rows, got %d", charHeight, len(combined['Y']))
+	}
+
+	// rendering must not crash and keeps rows aligned
+	got := RenderLine("AZ", combined)
+	if got[:5] != "A0ZZ\n" {
+		t.Errorf("unexpected first row %q", got[:5])
+	}
+}
@@ -0,0 +1,394 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// FIGlet fonts (.flf) start with a header line like:
+//
+//	flf2a$ 6 5 16 15 13 0 24463 229
+//
+// signature+hardblank, height, baseline, max length, old layout,
+// comment lines, and optionally print direction, full layout and
+// the number of code-tagged characters
+const figletSignature = "flf2a"
+
+// the 7 extra "Deutsch" characters every FIGlet font lists after '~'
+var figletDeutsch = []rune{196, 214, 220, 228, 246, 252, 223}
+
+// figletHeader holds the numbers we need from the first line of a .flf file
+type figletHeader struct {
+	hardblank     rune
+	height        int
+	baseline      int
+	oldLayout     int
+	commentLines  int
+	fullLayout    int
+	hasFullLayout bool
+}
+
+// parseFIGletHeader reads the header line of a FIGlet font
+func parseFIGletHeader(line string) (figletHeader, error) {
+	var h figletHeader
+
+	if !strings.HasPrefix(line, figletSignature) {
+		return h, fmt.Errorf("invalid FIGlet font: missing %q signature", figletSignature)
+	}
+
+	// the hardblank is the character right after the signature
+	rest := []rune(line[len(figletSignature):])
+	if len(rest) == 0 {
+		return h, fmt.Errorf("invalid FIGlet font: missing hardblank")
+	}
+	h.hardblank = rest[0]
+
+	// then come the numbers, separated by spaces
+	fields := strings.Fields(string(rest[1:]))
+	if len(fields) < 5 {
+		return h, fmt.Errorf("invalid FIGlet font: header needs at least 5 numbers, got %d", len(fields))
+	}
+
+	numbers := make([]int, len(fields))
+	for i, f := range fields {
+		n, err := strconv.Atoi(f)
+		if err != nil {
+			return h, fmt.Errorf("invalid FIGlet font: bad header number %q", f)
+		}
+		numbers[i] = n
+	}
+
+	h.height = numbers[0]
+	h.baseline = numbers[1]
+	h.oldLayout = numbers[3]
+	h.commentLines = numbers[4]
+	if len(numbers) > 6 {
+		h.fullLayout = numbers[6]
+		h.hasFullLayout = true
+	}
+
+	if h.height < 1 {
+		return h, fmt.Errorf("invalid FIGlet font: height must be at least 1, got %d", h.height)
+	}
+	if h.commentLines < 0 {
+		return h, fmt.Errorf("invalid FIGlet font: negative comment line count")
+	}
+
+	return h, nil
+}
+
+// layout turns the font's layout numbers into one of our layouts
+// the newer "full layout" number wins over the old one when present:
+// bits 1-32 are the smushing rules, 64 means fitting, 128 smushing
+func (h figletHeader) layout() Layout {
+	if h.hasFullLayout {
+		switch {
+		case h.fullLayout&128 != 0:
+			return Layout{Mode: LayoutSmush, Rules: SmushRule(h.fullLayout & 63)}
+		case h.fullLayout&64 != 0:
+			return Layout{Mode: LayoutFit}
+		default:
+			return Layout{Mode: LayoutFull}
+		}
+	}
+
+	// old layout: -1 full width, 0 fitting, otherwise the smushing rules
+	switch {
+	case h.oldLayout < 0:
+		return Layout{Mode: LayoutFull}
+	case h.oldLayout == 0:
+		return Layout{Mode: LayoutFit}
+	default:
+		return Layout{Mode: LayoutSmush, Rules: SmushRule(h.oldLayout & 63)}
+	}
+}
+
+// stripEndmarks removes the endmark characters at the end of a glyph row
+// the endmark is whatever the last character is (usually @), and the
+// last row of each glyph has it doubled (@@)
+func stripEndmarks(row string) string {
+	row = strings.TrimRight(row, " ")
+	if row == "" {
+		return row
+	}
+	runes := []rune(row)
+	mark := runes[len(runes)-1]
+	end := len(runes)
+	for end > 0 && runes[end-1] == mark {
+		end--
+	}
+	return string(runes[:end])
+}
+
+// parseFIGletCode reads the character code at the start of a code tag line
+// codes can be decimal (196), hex (0xC4) or octal (0304)
+func parseFIGletCode(line string) (int64, error) {
+	fields := strings.Fields(line)
+	if len(fields) == 0 {
+		return 0, fmt.Errorf("empty code tag")
+	}
+	// base 0 lets ParseInt understand the 0x and 0 prefixes
+	return strconv.ParseInt(fields[0], 0, 64)
+}
+
+// LoadFIGlet reads a FIGlet .flf font and turns it into a Banner
+// so it can be used exactly like the bundled banners
+func LoadFIGlet(path string) (Banner, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return parseFIGlet(string(data))
+}
+
+// parseFIGlet does the actual work for LoadFIGlet
+func parseFIGlet(content string) (Banner, error) {
+	banner, _, err := parseFIGletInfo(content)
+	return banner, err
+}
+
+// parseFIGletInfo is parseFIGlet that also returns what the font's header
+// says about its height, baseline and layout
+func parseFIGletInfo(content string) (Banner, BannerInfo, error) {
+	// same line ending handling as LoadBanner
+	content = strings.ReplaceAll(content, "\r\n", "\n")
+	content = strings.ReplaceAll(content, "\r", "\n")
+	lines := strings.Split(content, "\n")
+
+	header, err := parseFIGletHeader(lines[0])
+	if err != nil {
+		return nil, BannerInfo{}, err
+	}
+
+	// skip the header and the comment lines
+	pos := 1 + header.commentLines
+	if pos > len(lines) {
+		return nil, BannerInfo{}, fmt.Errorf("invalid FIGlet font: not enough comment lines")
+	}
+
+	banner := make(Banner)
+
+	// the endmark on the last row read, to tell the next glyph's rows
+	// from a code tag line
+	endmark := '@'
+
+	// readGlyph takes the next height lines as one character
+	readGlyph := func() ([]string, bool) {
+		if pos+header.height > len(lines) {
+			return nil, false
+		}
+		rows := make([]string, header.height)
+		for i := 0; i < header.height; i++ {
+			row := stripEndmarks(lines[pos+i])
+			// hardblanks print as spaces but stop characters being pushed
+			// together, so they are kept as our own hardblank marker
+			rows[i] = strings.ReplaceAll(row, string(header.hardblank), string(hardblank))
+		}
+		if last := []rune(strings.TrimRight(lines[pos+header.height-1], " ")); len(last) > 0 {
+			endmark = last[len(last)-1]
+		}
+		pos += header.height
+		return rows, true
+	}
+
+	// addGlyph stores a character; fonts fill the slots of characters they
+	// don't draw with zero-width glyphs, those are left out as missing
+	addGlyph := func(ch rune, rows []string) {
+		if glyphWidth(rows) > 0 {
+			banner[ch] = rows
+		}
+	}
+
+	// required characters: space to ~, in order
+	for code := firstChar; code <= lastChar; code++ {
+		rows, ok := readGlyph()
+		if !ok {
+			return nil, BannerInfo{}, fmt.Errorf("invalid FIGlet font: not enough lines for char %q", rune(code))
+		}
+		addGlyph(rune(code), rows)
+	}
+
+	// the Deutsch characters come next, but some fonts leave them out
+	for _, r := range figletDeutsch {
+		if pos < len(lines) && isFIGletCodeTag(lines[pos], endmark) {
+			// the font skipped straight to the code-tagged characters
+			break
+		}
+		rows, ok := readGlyph()
+		if !ok {
+			break
+		}
+		addGlyph(r, rows)
+	}
+
+	// anything left is a code-tagged character
+	for pos < len(lines) {
+		if strings.TrimSpace(lines[pos]) == "" {
+			pos++
+			continue
+		}
+		code, err := parseFIGletCode(lines[pos])
+		if err != nil {
+			return nil, BannerInfo{}, fmt.Errorf("invalid FIGlet font: bad code tag %q on line %d", lines[pos], pos+1)
+		}
+		pos++
+		rows, ok := readGlyph()
+		if !ok {
+			return nil, BannerInfo{}, fmt.Errorf("invalid FIGlet font: not enough lines for code %d", code)
+		}
+		// negative codes are translation-table entries, not real characters
+		if code >= 0 {
+			addGlyph(rune(code), rows)
+		}
+	}
+
+	info := BannerInfo{
+		Height:   header.height,
+		Baseline: header.baseline,
+		Layout:   header.layout().String(),
+	}
+	return banner, info, nil
+}
+
+// isFIGletCodeTag reports whether a line starts a code-tagged character
+// tag lines begin with a number, glyph rows end with the font's endmark
+// (any character, so the one the last glyph used is passed in)
+func isFIGletCodeTag(line string, endmark rune) bool {
+	if _, err := parseFIGletCode(line); err != nil {
+		return false
+	}
+	return !strings.HasSuffix(strings.TrimRight(line, " "), string(endmark))
+}
+
+// isFIGletPath reports whether a banner argument points to a FIGlet font file
+func isFIGletPath(name string) bool {
+	return strings.HasSuffix(strings.ToLower(name), ".flf")
+}
+
+// WriteFIGlet writes a banner as a FIGlet .flf font, so it can be used
+// with figlet and toilet; the name, author and license from info go into
+// the comment lines, and its baseline and layout into the header
+func WriteFIGlet(w io.Writer, b Banner, info BannerInfo) error {
+	height := b.Height()
+
+	// the hardblank must be a character no glyph uses
+	mark := pickUnused(b, "$&%~^")
+	if mark == 0 {
+		return fmt.Errorf("cannot write FIGlet font: no free character for the hardblank")
+	}
+
+	var comments []string
+	for _, key := range []string{"name", "author", "license"} {
+		if value := info.get(key); value != "" {
+			comments = append(comments, key+": "+value)
+		}
+	}
+
+	// without a known baseline, say the characters sit on the last row
+	baseline := info.Baseline
+	if baseline == 0 || baseline > height {
+		baseline = height
+	}
+
+	// the old layout number can't say "universal", figlet will fit instead
+	layout, _ := ParseLayout(info.Layout)
+	oldLayout, fullLayout := -1, 0
+	switch {
+	case info.Layout == "" || layout.Mode == LayoutFull:
+	case layout.Mode == LayoutFit:
+		oldLayout, fullLayout = 0, 64
+	default:
+		oldLayout, fullLayout = int(layout.Rules), 128|int(layout.Rules)
+	}
+
+	// the widest glyph row, plus room for the endmarks
+	maxLength := 0
+	for _, glyph := range b {
+		maxLength = max(maxLength, glyphWidth(glyph)+2)
+	}
+
+	out := bufio.NewWriter(w)
+	// signature+hardblank height baseline max-length old-layout comment-lines
+	// print-direction full-layout
+	fmt.Fprintf(out, "%s%c %d %d %d %d %d 0 %d\n", figletSignature, mark, height, baseline, maxLength, oldLayout, len(comments), fullLayout)
+	for _, line := range comments {
+		out.WriteString(line + "\n")
+	}
+
+	writeGlyph := func(glyph []string) {
+		glyph = fitGlyphHeight(glyph, height)
+		endmark := figletEndmark(glyph)
+		for row, line := range glyph {
+			line = strings.ReplaceAll(line, string(hardblank), string(mark))
+			out.WriteString(line + string(endmark))
+			if row == len(glyph)-1 {
+				// the last row of a character has a doubled endmark
+				out.WriteRune(endmark)
+			}
+			out.WriteString("\n")
+		}
+	}
+
+	// the required characters: space to ~, then the Deutsch ones
+	for code := firstChar; code <= lastChar; code++ {
+		writeGlyph(b[rune(code)])
+	}
+	for _, ch := range figletDeutsch {
+		writeGlyph(b[ch])
+	}
+
+	// everything else is a code-tagged character
+	var extra []rune
+	for ch := range b {
+		if (ch < firstChar || ch > lastChar) && !slices.Contains(figletDeutsch, ch) {
+			extra = append(extra, ch)
+		}
+	}
+	slices.Sort(extra)
+	for _, ch := range extra {
+		fmt.Fprintf(out, "0x%04X\n", ch)
+		writeGlyph(b[ch])
+	}
+
+	return out.Flush()
+}
+
+// pickUnused returns the first of the candidates that appears in no glyph
+func pickUnused(b Banner, candidates string) rune {
+	for _, c := range candidates {
+		used := false
+		for _, glyph := range b {
+			if strings.ContainsRune(strings.Join(glyph, ""), c) {
+				used = true
+				break
+			}
+		}
+		if !used {
+			return c
+		}
+	}
+	return 0
+}
+
+// figletEndmark picks the endmark for a glyph: @ unless a row ends with @
+// (figlet strips every trailing endmark, which would eat the art)
+func figletEndmark(glyph []string) rune {
+	for _, mark := range "@#|" {
+		clash := false
+		for _, row := range glyph {
+			if strings.HasSuffix(row, string(mark)) {
+				clash = true
+				break
+			}
+		}
+		if !clash {
+			return mark
+		}
+	}
+	return '@'
+}
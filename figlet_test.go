@@ -0,0 +1,161 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// makeFIGletFont builds a tiny .flf font where every character is 2 rows tall
+// each glyph is the character itself followed by a hardblank
+func makeFIGletFont(extra string) string {
+	var b strings.Builder
+	b.WriteString("flf2a$ 2 1 4 0 1\n")
+	b.WriteString("a test comment line\n")
+	for code := firstChar; code <= lastChar; code++ {
+		ch := string(rune(code))
+		if code == '@' {
+			// '@' is the endmark, so this glyph uses # instead
+			b.WriteString("@$#\n@$##\n")
+			continue
+		}
+		b.WriteString(ch + "$@\n")
+		b.WriteString(ch + "$@@\n")
+	}
+	b.WriteString(extra)
+	return b.String()
+}
+
+// writeTempFont saves font content in a temp dir and returns the path
+func writeTempFont(t *testing.T, content string) string {
+	path := filepath.Join(t.TempDir(), "test.flf")
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write font: %v", err)
+	}
+	return path
+}
+
+// test loading a basic FIGlet font
+func TestLoadFIGlet(t *testing.T) {
+	path := writeTempFont(t, makeFIGletFont(""))
+
+	banner, err := LoadFIGlet(path)
+	if err != nil {
+		t.Fatalf("failed to load font: %v", err)
+	}
+
+	// endmarks stripped, the font's hardblank ($) kept as our marker
+	if banner['A'][0] != "A"+string(hardblank) {
+		t.Errorf("expected row %q, got %q", "A"+string(hardblank), banner['A'][0])
+	}
+	if banner['@'][1] != "@"+string(hardblank) {
+		t.Errorf("expected row %q, got %q", "@"+string(hardblank), banner['@'][1])
+	}
+
+	// hardblanks print as spaces
+	if got := RenderLine("A", banner); got != "A \nA \n" {
+		t.Errorf("RenderLine(\"A\") = %q, want %q", got, "A \nA \n")
+	}
+}
+
+// test that Deutsch and code-tagged characters get loaded
+func TestLoadFIGletExtraCharacters(t *testing.T) {
+	extra := ""
+	for i := 0; i < len(figletDeutsch); i++ {
+		extra += "D@\nD@@\n"
+	}
+	extra += "0x3A9 GREEK CAPITAL LETTER OMEGA\nO@\nO@@\n"
+
+	banner, err := parseFIGlet(makeFIGletFont(extra))
+	if err != nil {
+		t.Fatalf("failed to parse font: %v", err)
+	}
+
+	if _, ok := banner['Ä']; !ok {
+		t.Error("expected Deutsch character Ä to be loaded")
+	}
+	if banner['Ω'][0] != "O" {
+		t.Errorf("expected code-tagged Ω, got %q", banner['Ω'])
+	}
+}
+
+// test that fonts without the Deutsch block still load their tagged characters
+func TestLoadFIGletSkippedDeutsch(t *testing.T) {
+	banner, err := parseFIGlet(makeFIGletFont("937\nO@\nO@@\n"))
+	if err != nil {
+		t.Fatalf("failed to parse font: %v", err)
+	}
+	if _, ok := banner['Ω']; !ok {
+		t.Error("expected code-tagged Ω to be loaded")
+	}
+}
+
+// test a font with # endmarks whose first Deutsch glyph starts with a number
+// (its rows must not be mistaken for a code tag)
+func TestLoadFIGletOtherEndmark(t *testing.T) {
+	font := strings.ReplaceAll(makeFIGletFont(""), "@\n", "#\n")
+	font = strings.ReplaceAll(font, "@#\n", "##\n")
+	font = strings.Replace(font, "@$#\n@$##\n", "@$%\n@$%%\n", 1)
+	for i := 0; i < len(figletDeutsch); i++ {
+		font += "7 #\n7 ##\n"
+	}
+
+	banner, err := parseFIGlet(font)
+	if err != nil {
+		t.Fatalf("failed to parse font: %v", err)
+	}
+	if banner['A'][1] != "A"+string(hardblank) {
+		t.Errorf("expected row %q, got %q", "A"+string(hardblank), banner['A'][1])
+	}
+	for _, ch := range figletDeutsch {
+		if got := banner[ch]; len(got) != 2 || got[0] != "7 " {
+			t.Errorf("expected Deutsch character %c drawn as 7, got %q", ch, got)
+		}
+	}
+}
+
+// test that a file without the flf2a signature is rejected
+func TestLoadFIGletBadHeader(t *testing.T) {
+	_, err := parseFIGlet("not a font\n")
+	if err == nil {
+		t.Error("expected error for missing signature")
+	}
+}
+
+// test that a banner exported as .flf loads back with the same glyphs
+func TestWriteFIGletRoundTrip(t *testing.T) {
+	banner, err := LoadBannerFS(builtinBanners, "banners/standard.txt")
+	if err != nil {
+		t.Fatalf("failed to load banner: %v", err)
+	}
+	// a glyph with a hardblank, a row ending in @ and a non-ASCII character
+	banner['Ω'] = []string{"O" + string(hardblank), "x@", "O@", "OO", "OO", "OO", "OO", "OO"}
+
+	var buf strings.Builder
+	info := BannerInfo{Name: "test", Baseline: 6, Layout: "smush"}
+	if err := WriteFIGlet(&buf, banner, info); err != nil {
+		t.Fatalf("failed to write font: %v", err)
+	}
+	if !strings.HasPrefix(buf.String(), "flf2a$ 8 6 ") {
+		t.Errorf("unexpected header %q", strings.SplitN(buf.String(), "\n", 2)[0])
+	}
+
+	loaded, loadedInfo, err := parseFIGletInfo(buf.String())
+	if err != nil {
+		t.Fatalf("failed to parse exported font: %v", err)
+	}
+	if loadedInfo.Baseline != 6 || loadedInfo.Layout != "smush" {
+		t.Errorf("expected baseline 6 and layout smush, got %+v", loadedInfo)
+	}
+	if len(loaded) != len(banner) {
+		t.Errorf("expected %d characters, got %d", len(banner), len(loaded))
+	}
+	for ch, glyph := range banner {
+		for row := range glyph {
+			if loaded[ch][row] != glyph[row] {
+				t.Errorf("%q row %d: got %q, want %q", ch, row, loaded[ch][row], glyph[row])
+			}
+		}
+	}
+}
@@ -0,0 +1,67 @@
+package main
+
+import "strings"
+
+// hardblank marks a FIGlet "hard blank": it prints as a space, but the
+// layout modes treat it as ink so characters can't be pushed through it
+// (FIGlet fonts pick their own hardblank character; the loader maps it to this)
+const hardblank = '\uE000'
+
+// cell is one character of rendered output and the color it's drawn in
+type cell struct {
+	ch    rune
+	color string // ANSI color code, "" for no color
+	owner int    // position of the input character that drew this cell
+}
+
+// grid is a rendered block of text, one slice of cells per row
+type grid [][]cell
+
+// String turns the grid into text, one line per row
+// colored cells are wrapped in color + reset codes, one span per input
+// character, the same way renderSingleLineWithColor always has
+func (g grid) String() string {
+	var builder strings.Builder
+	for _, row := range g {
+		writeCells(&builder, row)
+		builder.WriteRune('\n')
+	}
+	return builder.String()
+}
+
+// writeCells writes one row of cells, opening and closing color spans
+func writeCells(builder *strings.Builder, row []cell) {
+	open := false
+	for i, c := range row {
+		// a new span starts when the color or the owning character changes
+		startSpan := c.color != "" && (i == 0 || !open || row[i-1].color != c.color || row[i-1].owner != c.owner)
+		if open && (c.color == "" || startSpan) {
+			builder.WriteString(ResetColor)
+			open = false
+		}
+		if startSpan {
+			builder.WriteString(c.color)
+			open = true
+		}
+		if c.ch == hardblank {
+			builder.WriteRune(' ')
+		} else {
+			builder.WriteRune(c.ch)
+		}
+	}
+	if open {
+		builder.WriteString(ResetColor)
+	}
+}
+
+// glyphCells turns a glyph into rows of cells drawn by the character
+// at position owner, in the given color ("" for none)
+func glyphCells(glyph []string, height int, color string, owner int) [][]cell {
+	cells := make([][]cell, height)
+	for row := 0; row < height && row < len(glyph); row++ {
+		for _, r := range glyph[row] {
+			cells[row] = append(cells[row], cell{ch: r, color: color, owner: owner})
+		}
+	}
+	return cells
+}
@@ -0,0 +1,121 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// BannerInfo is the optional metadata at the top of a banner file:
+//
+//	# name: Standard
+//	# author: Glenn Chappell
+//	# license: BSD-3-Clause
+//	# height: 8
+//	# baseline: 6
+//	# ink: _|/\()
+//	# layout: smush
+//
+// every line is optional; files without any of it load as always
+type BannerInfo struct {
+	Name     string
+	Author   string
+	License  string
+	Height   int    // rows per character, 0 = detect from the file
+	Baseline int    // rows from the top down to the baseline, 0 = unknown
+	Ink      string // characters that count as ink, "" = anything but spaces
+	Layout   string // default --layout for this banner, "" = full
+}
+
+// bannerInfoKeys lists the header keys in the order they are written
+var bannerInfoKeys = []string{"name", "author", "license", "height", "baseline", "ink", "layout"}
+
+// parseBannerHeader reads the "# key: value" lines at the top of a banner
+// file and returns the info plus how many lines the header takes up
+// (on error, that is the number of the bad line)
+// "#" lines without a known key are comments; unknown keys are ignored
+// so newer files still load
+func parseBannerHeader(lines []string) (BannerInfo, int, error) {
+	var info BannerInfo
+	n := 0
+	for n < len(lines) && strings.HasPrefix(lines[n], "#") {
+		key, value, ok := strings.Cut(strings.TrimPrefix(lines[n], "#"), ":")
+		n++
+		if !ok {
+			continue
+		}
+		if err := info.set(strings.ToLower(strings.TrimSpace(key)), strings.TrimSpace(value)); err != nil {
+			return info, n, err
+		}
+	}
+	return info, n, nil
+}
+
+// set stores one header value, checking numbers and layouts
+func (info *BannerInfo) set(key, value string) error {
+	switch key {
+	case "name":
+		info.Name = value
+	case "author":
+		info.Author = value
+	case "license":
+		info.License = value
+	case "height", "baseline":
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 1 {
+			return fmt.Errorf("%s must be a positive number, got %q", key, value)
+		}
+		if key == "height" {
+			info.Height = n
+		} else {
+			info.Baseline = n
+		}
+	case "ink":
+		info.Ink = value
+	case "layout":
+		if _, err := ParseLayout(value); err != nil {
+			return err
+		}
+		info.Layout = value
+	}
+	return nil
+}
+
+// get returns one header value as text ("" when not set)
+func (info BannerInfo) get(key string) string {
+	switch key {
+	case "name":
+		return info.Name
+	case "author":
+		return info.Author
+	case "license":
+		return info.License
+	case "height", "baseline":
+		n := info.Height
+		if key == "baseline" {
+			n = info.Baseline
+		}
+		if n == 0 {
+			return ""
+		}
+		return strconv.Itoa(n)
+	case "ink":
+		return info.Ink
+	case "layout":
+		return info.Layout
+	}
+	return ""
+}
+
+// writeBannerHeader writes the "# key: value" lines for every field that is set
+func writeBannerHeader(w io.Writer, info BannerInfo) error {
+	for _, key := range bannerInfoKeys {
+		if value := info.get(key); value != "" {
+			if _, err := fmt.Fprintf(w, "# %s: %s\n", key, value); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
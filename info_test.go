@@ -0,0 +1,103 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// makeHeaderBanner builds a banner file with the given header lines
+// and 95 characters that are 3 rows tall
+func makeHeaderBanner(header string) string {
+	var content strings.Builder
+	content.WriteString(header)
+	for c := firstChar; c <= lastChar; c++ {
+		content.WriteString("\n")
+		for row := 0; row < 3; row++ {
+			content.WriteString(fmt.Sprintf("%c%d\n", c, row))
+		}
+	}
+	return content.String()
+}
+
+// test that the header is read into BannerInfo
+func TestParseBannerInfo(t *testing.T) {
+	header := "# name: Tiny\n# author: Jane Doe\n# license: MIT\n# height: 3\n# baseline: 2\n# ink: #@\n# layout: fit\n# just a comment\n"
+	banner, info, err := parseBannerInfo([]byte(makeHeaderBanner(header)))
+	if err != nil {
+		t.Fatalf("failed to parse banner: %v", err)
+	}
+
+	want := BannerInfo{Name: "Tiny", Author: "Jane Doe", License: "MIT", Height: 3, Baseline: 2, Ink: "#@", Layout: "fit"}
+	if info != want {
+		t.Errorf("got %+v, want %+v", info, want)
+	}
+	if banner['A'][0] != "A0" || banner.Height() != 3 {
+		t.Errorf("glyphs not loaded after the header: %q", banner['A'])
+	}
+}
+
+// test that files without a header still load, with empty info
+func TestParseBannerInfoNoHeader(t *testing.T) {
+	_, info, err := parseBannerInfo([]byte(makeHeaderBanner("")))
+	if err != nil {
+		t.Fatalf("failed to parse banner: %v", err)
+	}
+	if info != (BannerInfo{}) {
+		t.Errorf("expected empty info, got %+v", info)
+	}
+}
+
+// test that bad header values are reported with their line
+func TestParseBannerInfoBadHeader(t *testing.T) {
+	for _, header := range []string{"# height: tall\n", "# name: x\n# layout: sideways\n"} {
+		_, _, err := parseBannerInfo([]byte(makeHeaderBanner(header)))
+		if err == nil {
+			t.Errorf("expected error for header %q", header)
+		}
+	}
+}
+
+// test that a written header reads back the same
+func TestWriteBannerInfoRoundTrip(t *testing.T) {
+	info := BannerInfo{Name: "Round", Author: "Me", Height: 8, Baseline: 6, Layout: "smush:equal"}
+
+	var buf strings.Builder
+	if err := WriteBannerInfo(&buf, fakeBanner(), info); err != nil {
+		t.Fatalf("failed to write banner: %v", err)
+	}
+	_, got, err := parseBannerInfo([]byte(buf.String()))
+	if err != nil {
+		t.Fatalf("failed to read banner back: %v", err)
+	}
+	if got != info {
+		t.Errorf("got %+v, want %+v", got, info)
+	}
+}
+
+// test that Layout.String gives back what ParseLayout reads
+func TestLayoutStringRoundTrip(t *testing.T) {
+	for _, value := range []string{"full", "fit", "smush", "universal", "smush:equal,bigx"} {
+		layout, err := ParseLayout(value)
+		if err != nil {
+			t.Fatalf("ParseLayout(%q) failed: %v", value, err)
+		}
+		if layout.String() != value {
+			t.Errorf("ParseLayout(%q).String() = %q", value, layout.String())
+		}
+	}
+}
+
+// test that fallback glyphs are moved onto the main banner's baseline
+func TestFallbackBaseline(t *testing.T) {
+	primary := Banner{'A': {"A", "A", "A", "A"}} // baseline 3: one row below it
+	short := Banner{'z': {"z", "z"}}             // baseline 2: sits on its last row
+	combined := withFallbackAligned(primary, 3, []Banner{short}, []int{2})
+
+	want := []string{" ", "z", "z", " "}
+	for row := range want {
+		if combined['z'][row] != want[row] {
+			t.Errorf("row %d: got %q, want %q", row, combined['z'][row], want[row])
+		}
+	}
+}
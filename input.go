@@ -0,0 +1,58 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+)
+
+// stdinName is the input file name that means "read the text from stdin"
+const stdinName = "-"
+
+// byteOrderMark is what some editors (mostly on Windows) put at the start
+// of a UTF-8 file; it isn't part of the text
+const byteOrderMark = "\uFEFF"
+
+// isPiped reports whether f is a pipe or a file rather than a terminal,
+// e.g. stdin in `echo Hello | go run .` or `go run . < notes.txt`
+func isPiped(f *os.File) bool {
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeNamedPipe != 0 || info.Mode().IsRegular()
+}
+
+// openInput opens the file to read the text from ("-" is stdin)
+func openInput(name string) (io.ReadCloser, error) {
+	if name == stdinName {
+		return io.NopCloser(os.Stdin), nil
+	}
+	file, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	// a folder opens fine but can't be read
+	if info, err := file.Stat(); err == nil && info.IsDir() {
+		file.Close()
+		return nil, fmt.Errorf("%s is a directory", name)
+	}
+	return file, nil
+}
+
+// RenderFrom is RenderTo for text read from in (a file or stdin), drawn
+// a line at a time as it's read; substring is colored wherever it appears
+// in a line ("" colors everything), as the text isn't known up front
+func RenderFrom(w io.Writer, in io.Reader, banner Banner, colorCode, substring string, opts RenderOptions) error {
+	renderer := NewRenderer(w, banner, opts)
+	if colorCode != "" {
+		renderer.Color = colorCode
+		renderer.Match = func(line string) []int {
+			return FindSubstringIndexes(line, substring)
+		}
+	}
+	if _, err := renderer.ReadFrom(in); err != nil {
+		return err
+	}
+	return renderer.Close()
+}
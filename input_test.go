@@ -0,0 +1,123 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// test where the text comes from with --input, - and piped stdin
+func TestParseColorArgs_Input(t *testing.T) {
+	tests := []struct {
+		args      []string
+		piped     bool
+		input     string
+		text      string
+		substring string
+		banner    string
+	}{
+		{[]string{"program", "--input=notes.txt"}, false, "notes.txt", "", "", "standard"},
+		{[]string{"program", "--input=notes.txt", "shadow"}, false, "notes.txt", "", "", "shadow"},
+		{[]string{"program", "--color=red", "--input=notes.txt", "kit"}, false, "notes.txt", "", "kit", "standard"},
+		{[]string{"program", "--color=red", "--input=notes.txt", "kit", "shadow"}, false, "notes.txt", "", "kit", "shadow"},
+		{[]string{"program", "-"}, false, "-", "", "", "standard"},
+		{[]string{"program", "-", "shadow"}, false, "-", "", "", "shadow"},
+		{[]string{"program", "--color=red", "kit", "-"}, false, "-", "", "kit", "standard"},
+		{[]string{"program"}, true, "-", "", "", "standard"},
+		{[]string{"program", "--color=red"}, true, "-", "", "", "standard"},
+		// text given as an argument wins over piped stdin
+		{[]string{"program", "Hello"}, true, "", "Hello", "", "standard"},
+	}
+
+	for _, tt := range tests {
+		opts, err := ParseColorArgsStdin(tt.args, tt.piped)
+		if err != nil {
+			t.Errorf("%v: unexpected error: %v", tt.args, err)
+			continue
+		}
+		if opts.InputFile != tt.input || opts.Text != tt.text || opts.Substring != tt.substring || opts.Banner != tt.banner {
+			t.Errorf("%v: expected input %q, text %q, substring %q, banner %q; got %q, %q, %q, %q",
+				tt.args, tt.input, tt.text, tt.substring, tt.banner, opts.InputFile, opts.Text, opts.Substring, opts.Banner)
+		}
+	}
+}
+
+// test the argument mistakes when the text comes from a file
+func TestParseColorArgs_InputErrors(t *testing.T) {
+	for _, args := range [][]string{
+		{"program"},
+		{"program", "--input="},
+		{"program", "--input=notes.txt", "nope"},
+		{"program", "--input=notes.txt", "kit", "shadow"},
+		{"program", "--color=red", "--input=notes.txt", "a", "b", "c"},
+	} {
+		if _, err := ParseColorArgsStdin(args, false); err == nil {
+			t.Errorf("%v: expected an error", args)
+		}
+	}
+}
+
+// test that text read in renders like the same text given as an argument
+func TestRenderFrom(t *testing.T) {
+	banner, err := LoadBanner("banners/standard.txt")
+	if err != nil {
+		t.Fatalf("failed to load banner: %v", err)
+	}
+
+	tests := []struct {
+		read string
+		text string
+	}{
+		{"Hello\nWorld", "Hello\nWorld"},
+		{"Hello\n", "Hello\n"},
+		{"Hello\r\nWorld\r\n", "Hello\nWorld\n"},
+		{"\uFEFFHello", "Hello"},
+		{"\uFEFFHi\r\n\r\nthere", "Hi\n\nthere"},
+		{"", ""},
+	}
+
+	for _, tt := range tests {
+		var out bytes.Buffer
+		if err := RenderFrom(&out, strings.NewReader(tt.read), banner, "", "", RenderOptions{}); err != nil {
+			t.Fatalf("%q: RenderFrom failed: %v", tt.read, err)
+		}
+		if want := RenderInput(tt.text, banner); out.String() != want {
+			t.Errorf("%q: expected the art for %q, got %q", tt.read, tt.text, out.String())
+		}
+	}
+}
+
+// test that the substring is found in each line that's read
+func TestRenderFromColor(t *testing.T) {
+	var out bytes.Buffer
+	RenderFrom(&out, strings.NewReader("ab\r\nba\n"), wrapBanner, "\033[31m", "b", RenderOptions{})
+
+	want := "a\033[31mb\033[0m\n\033[31mb\033[0ma\n\n"
+	if out.String() != want {
+		t.Errorf("expected %q, got %q", want, out.String())
+	}
+}
+
+// test opening the input file
+func TestOpenInput(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "notes.txt")
+	if err := os.WriteFile(path, []byte("Hi"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	file, err := openInput(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	file.Close()
+
+	if _, err := openInput(dir); err == nil {
+		t.Error("expected an error for a directory")
+	}
+	if _, err := openInput(filepath.Join(dir, "missing.txt")); err == nil {
+		t.Error("expected an error for a missing file")
+	}
+}
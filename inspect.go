@@ -0,0 +1,52 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf8"
+)
+
+// trailingMark is drawn in place of trailing spaces by inspect,
+// so rows that are padded differently can be told apart
+const trailingMark = '·'
+
+// WriteGlyphInspection prints one glyph for debugging a banner:
+// a column ruler on top, then each row with its row number, its line in
+// the banner file, the row between | bars with trailing spaces shown as ·,
+// and the row's width
+// firstLine is the file line of the glyph's first row (0 if unknown)
+func WriteGlyphInspection(w io.Writer, ch rune, glyph []string, firstLine int) {
+	if firstLine > 0 {
+		fmt.Fprintf(w, "%q (U+%04X): lines %d-%d\n", ch, ch, firstLine, firstLine+len(glyph)-1)
+	} else {
+		fmt.Fprintf(w, "%q (U+%04X)\n", ch, ch)
+	}
+
+	// the ruler numbers the columns: tens on the first line, units below
+	width := glyphWidth(glyph)
+	var tens, units strings.Builder
+	for col := 0; col < width; col++ {
+		if col%10 == 0 {
+			tens.WriteString(fmt.Sprint(col / 10 % 10))
+		} else {
+			tens.WriteRune(' ')
+		}
+		units.WriteString(fmt.Sprint(col % 10))
+	}
+	// "row" and "line" columns, then the bar before the art
+	indent := strings.Repeat(" ", 11)
+	fmt.Fprintf(w, "%s%s\n%s%s\n", indent, tens.String(), indent, units.String())
+
+	for row := range glyph {
+		text := glyphRowText(glyph, row)
+		trimmed := strings.TrimRight(text, " ")
+		text = trimmed + strings.Repeat(string(trailingMark), len(text)-len(trimmed))
+
+		line := "   -"
+		if firstLine > 0 {
+			line = fmt.Sprintf("%4d", firstLine+row)
+		}
+		fmt.Fprintf(w, "%3d %s  |%s|  width %d\n", row+1, line, text, utf8.RuneCountInString(text))
+	}
+}
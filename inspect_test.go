@@ -0,0 +1,45 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+// test the rulers, widths and trailing space markers
+func TestWriteGlyphInspection(t *testing.T) {
+	var out bytes.Buffer
+	WriteGlyphInspection(&out, 'I', []string{"|  ", "|"}, 10)
+	text := out.String()
+
+	for _, want := range []string{
+		"'I' (U+0049): lines 10-11\n",
+		"           012\n",
+		"  1   10  ||··|  width 3\n",
+		"  2   11  |||  width 1\n",
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("expected %q in:\n%s", want, text)
+		}
+	}
+}
+
+// test that inspect finds the lines of a character in a banner file
+func TestRunInspect(t *testing.T) {
+	var out, errOut bytes.Buffer
+	if status := runInspect([]string{"standard", "A"}, &out, &errOut); status != 0 {
+		t.Fatalf("expected status 0, got %d: %s", status, errOut.String())
+	}
+	// 'A' is the 34th block of 9 lines, so its rows start on line 299
+	if !strings.Contains(out.String(), "'A' (U+0041): lines 299-306") {
+		t.Errorf("expected the line range of 'A', got:\n%s", out.String())
+	}
+
+	out.Reset()
+	if status := runInspect([]string{"standard", "€"}, &out, &errOut); status != 1 {
+		t.Errorf("expected status 1 for a missing character, got %d", status)
+	}
+	if status := runInspect([]string{"standard"}, &out, &errOut); status != 2 {
+		t.Errorf("expected status 2 for bad usage, got %d", status)
+	}
+}
@@ -0,0 +1,262 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// LayoutMode says how neighbouring characters are put next to each other
+type LayoutMode int
+
+const (
+	LayoutFull  LayoutMode = iota // side by side, exactly as drawn (the default)
+	LayoutFit                     // pushed together until they touch (kerning)
+	LayoutSmush                   // pushed one column further, merging the touching characters
+)
+
+// SmushRule is a set of FIGlet smushing rules, combined with |
+// with no rules at all, smushing is "universal": the later character wins
+type SmushRule int
+
+const (
+	SmushEqual        SmushRule = 1 << iota // two equal characters become one
+	SmushUnderscore                         // _ is replaced by | / \ [ ] { } ( ) < >
+	SmushHierarchy                          // of | /\ [] {} () <>, the later class wins
+	SmushOppositePair                       // ][ }{ )( and the like become |
+	SmushBigX                               // /\ becomes |, \/ becomes Y, >< becomes X
+	SmushHardblank                          // two hardblanks become one
+
+	SmushAll = SmushEqual | SmushUnderscore | SmushHierarchy | SmushOppositePair | SmushBigX | SmushHardblank
+)
+
+// smushRuleNames are the names used in --layout=smush:<rules>
+var smushRuleNames = map[string]SmushRule{
+	"equal":      SmushEqual,
+	"underscore": SmushUnderscore,
+	"hierarchy":  SmushHierarchy,
+	"pair":       SmushOppositePair,
+	"bigx":       SmushBigX,
+	"hardblank":  SmushHardblank,
+}
+
+// Layout is a layout mode plus, for smushing, which rules to use
+type Layout struct {
+	Mode  LayoutMode
+	Rules SmushRule
+}
+
+// ParseLayout reads a --layout value:
+//   - "full": characters side by side (default)
+//   - "fit" or "kern": characters moved together until they touch
+//   - "smush": characters overlap by a column, merged by all the rules
+//   - "smush:equal,bigx": smushing with only the listed rules
+//   - "universal": smushing where the later character simply wins
+func ParseLayout(value string) (Layout, error) {
+	switch value {
+	case "full":
+		return Layout{Mode: LayoutFull}, nil
+	case "fit", "kern":
+		return Layout{Mode: LayoutFit}, nil
+	case "smush":
+		return Layout{Mode: LayoutSmush, Rules: SmushAll}, nil
+	case "universal":
+		return Layout{Mode: LayoutSmush}, nil
+	}
+
+	names, ok := strings.CutPrefix(value, "smush:")
+	if !ok {
+		return Layout{}, fmt.Errorf("unknown layout %q (expected full, fit, smush, smush:<rules> or universal)", value)
+	}
+
+	layout := Layout{Mode: LayoutSmush}
+	for _, name := range strings.Split(names, ",") {
+		rule, ok := smushRuleNames[name]
+		if !ok {
+			return Layout{}, fmt.Errorf("unknown smush rule %q (expected equal, underscore, hierarchy, pair, bigx or hardblank)", name)
+		}
+		layout.Rules |= rule
+	}
+	return layout, nil
+}
+
+// String gives the --layout value for this layout (ParseLayout reads it back)
+func (l Layout) String() string {
+	switch {
+	case l.Mode == LayoutFull:
+		return "full"
+	case l.Mode == LayoutFit:
+		return "fit"
+	case l.Rules == 0:
+		return "universal"
+	case l.Rules == SmushAll:
+		return "smush"
+	}
+
+	var names []string
+	for _, name := range []string{"equal", "underscore", "hierarchy", "pair", "bigx", "hardblank"} {
+		if l.Rules&smushRuleNames[name] != 0 {
+			names = append(names, name)
+		}
+	}
+	return "smush:" + strings.Join(names, ",")
+}
+
+// the classes used by the hierarchy rule, lowest to highest
+var smushHierarchy = []string{"|", "/\\", "[]", "{}", "()", "<>"}
+
+// smushChars works out what two touching characters merge into
+// returns 0 when this layout doesn't let them merge
+// leftWidth and rightWidth are the widths of the two glyphs: like figlet,
+// glyphs narrower than 2 columns never smush
+func (l Layout) smushChars(left, right rune, leftWidth, rightWidth int) rune {
+	if l.Mode != LayoutSmush || leftWidth < 2 || rightWidth < 2 {
+		return 0
+	}
+
+	// universal smushing: the later character wins over anything but a hardblank
+	if l.Rules == 0 {
+		if right == hardblank {
+			return left
+		}
+		return right
+	}
+
+	if left == hardblank || right == hardblank {
+		if left == right && l.Rules&SmushHardblank != 0 {
+			return left
+		}
+		return 0
+	}
+
+	if l.Rules&SmushEqual != 0 && left == right {
+		return left
+	}
+
+	if l.Rules&SmushUnderscore != 0 {
+		const borders = "|/\\[]{}()<>"
+		if left == '_' && strings.ContainsRune(borders, right) {
+			return right
+		}
+		if right == '_' && strings.ContainsRune(borders, left) {
+			return left
+		}
+	}
+
+	if l.Rules&SmushHierarchy != 0 {
+		leftClass, rightClass := -1, -1
+		for i, class := range smushHierarchy {
+			if strings.ContainsRune(class, left) {
+				leftClass = i
+			}
+			if strings.ContainsRune(class, right) {
+				rightClass = i
+			}
+		}
+		if leftClass >= 0 && rightClass >= 0 && leftClass != rightClass {
+			if leftClass > rightClass {
+				return left
+			}
+			return right
+		}
+	}
+
+	if l.Rules&SmushOppositePair != 0 {
+		switch string([]rune{left, right}) {
+		case "[]", "][", "{}", "}{", "()", ")(":
+			return '|'
+		}
+	}
+
+	if l.Rules&SmushBigX != 0 {
+		switch string([]rune{left, right}) {
+		case "/\\":
+			return '|'
+		case "\\/":
+			return 'Y'
+		case "><":
+			return 'X'
+		}
+	}
+
+	return 0
+}
+
+// overlap works out how many columns the next glyph can be pulled left
+// over the rows rendered so far: the smallest amount over all rows
+func (l Layout) overlap(rows grid, glyph [][]cell, prevWidth, width int) int {
+	if l.Mode == LayoutFull || len(rows) == 0 || len(rows[0]) == 0 {
+		return 0
+	}
+
+	amount := width
+	for row := range rows {
+		out, next := rows[row], glyph[row]
+
+		// blank columns at the end of the output and start of the glyph
+		trailing := 0
+		for trailing < len(out) && out[len(out)-1-trailing].ch == ' ' {
+			trailing++
+		}
+		leading := 0
+		for leading < len(next) && next[leading].ch == ' ' {
+			leading++
+		}
+		rowAmount := trailing + leading
+
+		// smushing lets the touching characters share one more column
+		if trailing < len(out) && leading < len(next) {
+			left, right := out[len(out)-1-trailing].ch, next[leading].ch
+			if l.smushChars(left, right, prevWidth, width) != 0 {
+				rowAmount++
+			}
+		}
+
+		amount = min(amount, rowAmount)
+	}
+
+	return min(amount, len(rows[0]))
+}
+
+// addGlyph adds a glyph to the end of the rendered rows, overlapping
+// the last columns by the amount the layout allows
+func (l Layout) addGlyph(rows grid, glyph [][]cell, prevWidth, width int) {
+	// fitting and smushing work on rectangles, so pad ragged rows
+	if l.Mode != LayoutFull {
+		for row := range glyph {
+			for len(glyph[row]) < width {
+				glyph[row] = append(glyph[row], cell{ch: ' ', owner: -1})
+			}
+		}
+	}
+
+	amount := l.overlap(rows, glyph, prevWidth, width)
+
+	for row := range rows {
+		out := rows[row]
+		for k := 0; k < amount; k++ {
+			i := len(out) - amount + k
+			out[i] = l.smushCells(out[i], glyph[row][k], prevWidth, width)
+		}
+		rows[row] = append(out, glyph[row][amount:]...)
+	}
+}
+
+// smushCells merges two overlapping cells; a cell keeps its color
+// when its character is the one that survives
+func (l Layout) smushCells(left, right cell, leftWidth, rightWidth int) cell {
+	if left.ch == ' ' {
+		return right
+	}
+	if right.ch == ' ' {
+		return left
+	}
+	merged := l.smushChars(left.ch, right.ch, leftWidth, rightWidth)
+	if merged == 0 || merged == right.ch {
+		return right
+	}
+	if merged == left.ch {
+		return left
+	}
+	// a brand new character (like the | from "/\") takes the right side's color
+	return cell{ch: merged, color: right.color, owner: right.owner}
+}
@@ -0,0 +1,125 @@
+package main
+
+import "testing"
+
+// make a banner with 2-row glyphs that have blank edges, for the layout modes
+func layoutBanner() Banner {
+	return Banner{
+		'/':  {" /", "/ "},
+		'\\': {"\\ ", " \\"},
+		'|':  {"| ", "| "},
+		'_':  {"  ", "__"},
+		'h':  {"h" + string(hardblank), "h" + string(hardblank)},
+	}
+}
+
+// test parsing the --layout values
+func TestParseLayout(t *testing.T) {
+	tests := map[string]Layout{
+		"full":             {Mode: LayoutFull},
+		"fit":              {Mode: LayoutFit},
+		"kern":             {Mode: LayoutFit},
+		"smush":            {Mode: LayoutSmush, Rules: SmushAll},
+		"universal":        {Mode: LayoutSmush},
+		"smush:equal,bigx": {Mode: LayoutSmush, Rules: SmushEqual | SmushBigX},
+		"smush:pair":       {Mode: LayoutSmush, Rules: SmushOppositePair},
+		"smush:hardblank":  {Mode: LayoutSmush, Rules: SmushHardblank},
+		"smush:underscore": {Mode: LayoutSmush, Rules: SmushUnderscore},
+		"smush:hierarchy":  {Mode: LayoutSmush, Rules: SmushHierarchy},
+	}
+	for value, want := range tests {
+		got, err := ParseLayout(value)
+		if err != nil {
+			t.Errorf("ParseLayout(%q) failed: %v", value, err)
+		} else if got != want {
+			t.Errorf("ParseLayout(%q) = %+v, want %+v", value, got, want)
+		}
+	}
+
+	for _, bad := range []string{"", "tight", "smush:", "smush:equal,nope"} {
+		if _, err := ParseLayout(bad); err == nil {
+			t.Errorf("ParseLayout(%q) should fail", bad)
+		}
+	}
+}
+
+// test each smushing rule on its own
+func TestSmushChars(t *testing.T) {
+	tests := []struct {
+		rules       SmushRule
+		left, right rune
+		want        rune
+	}{
+		{SmushEqual, '|', '|', '|'},
+		{SmushEqual, '|', '/', 0},
+		{SmushUnderscore, '_', '|', '|'},
+		{SmushUnderscore, '(', '_', '('},
+		{SmushHierarchy, '|', '/', '/'},
+		{SmushHierarchy, '<', '[', '<'},
+		{SmushOppositePair, ']', '[', '|'},
+		{SmushOppositePair, '(', ')', '|'},
+		{SmushBigX, '/', '\\', '|'},
+		{SmushBigX, '\\', '/', 'Y'},
+		{SmushBigX, '>', '<', 'X'},
+		{SmushHardblank, hardblank, hardblank, hardblank},
+		{SmushAll, hardblank, '|', 0},
+		{0, 'a', 'b', 'b'}, // universal: the later character wins
+		{0, 'a', hardblank, 'a'},
+	}
+	for _, tt := range tests {
+		layout := Layout{Mode: LayoutSmush, Rules: tt.rules}
+		if got := layout.smushChars(tt.left, tt.right, 2, 2); got != tt.want {
+			t.Errorf("rules %b: smush %q + %q = %q, want %q", tt.rules, tt.left, tt.right, got, tt.want)
+		}
+	}
+
+	// narrow glyphs never smush
+	layout := Layout{Mode: LayoutSmush, Rules: SmushAll}
+	if got := layout.smushChars('|', '|', 1, 2); got != 0 {
+		t.Errorf("expected no smushing for a 1-wide glyph, got %q", got)
+	}
+}
+
+// test the three layout modes on the same text
+func TestRenderLayouts(t *testing.T) {
+	b := layoutBanner()
+	tests := []struct {
+		layout Layout
+		want   string
+	}{
+		{Layout{Mode: LayoutFull}, " /\\ \n/  \\\n"},
+		{Layout{Mode: LayoutFit}, " /\\ \n/  \\\n"},
+		{Layout{Mode: LayoutSmush, Rules: SmushAll}, " | \n/ \\\n"},
+	}
+	for _, tt := range tests {
+		got := RenderInputOptions("/\\", b, RenderOptions{Layout: tt.layout})
+		if got != tt.want {
+			t.Errorf("layout %+v: got %q, want %q", tt.layout, got, tt.want)
+		}
+	}
+
+	// fitting removes the blank columns between | and |
+	got := RenderInputOptions("||", b, RenderOptions{Layout: Layout{Mode: LayoutFit}})
+	if got != "|| \n|| \n" {
+		t.Errorf("fit ||: got %q", got)
+	}
+}
+
+// test that hardblanks stop fitting but print as spaces
+func TestRenderLayoutHardblank(t *testing.T) {
+	got := RenderInputOptions("h|", layoutBanner(), RenderOptions{Layout: Layout{Mode: LayoutFit}})
+	if got != "h | \nh | \n" {
+		t.Errorf("got %q", got)
+	}
+}
+
+// test that colored output keeps its color codes when characters are smushed
+func TestRenderLayoutColor(t *testing.T) {
+	opts := RenderOptions{Layout: Layout{Mode: LayoutFit}}
+	got := RenderWithColorOptions("||", layoutBanner(), "<c>", []int{1}, opts)
+	// only the second | is colored, its cells form one span per row
+	want := "|<c>| " + ResetColor + "\n|<c>| " + ResetColor + "\n"
+	if got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
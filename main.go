@@ -1,11 +1,20 @@
 package main
 
 import (
+	"bufio"
+	"errors"
 	"fmt"
+	"io"
 	"os"
+	"strings"
 )
 
 func main() {
+	// Step 0: Subcommands like `validate <banner>` do their own thing
+	if handled, status := runCommand(os.Args, os.Stdout, os.Stderr); handled {
+		os.Exit(status)
+	}
+
 	// Step 1: Parse the command line arguments
 	// This function reads os.Args and figures out:
 	// - Do we need color? (UseColor)
@@ -13,7 +22,8 @@ func main() {
 	// - What substring to color? (Substring)
 	// - What text to render? (Text)
 	// - What banner to use? (Banner)
-	opts, err := ParseColorArgs(os.Args)
+	// - Is the text in a file or piped in instead? (InputFile)
+	opts, err := ParseColorArgsStdin(os.Args, isPiped(os.Stdin))
 
 	// Step 2: Check if parsing failed
 	// If there was an error (wrong format, missing args, etc.)
@@ -27,42 +37,70 @@ func main() {
 		return // Exit the program
 	}
 
-	// Step 3: Determine which banner file to load
-	// Based on the banner name (standard, shadow, or thinkertoy)
-	// we set the correct file path
-	var bannerPath string
-	switch opts.Banner {
-	case "standard":
-		// User wants standard banner
-		bannerPath = "banners/standard.txt"
-	case "shadow":
-		// User wants shadow banner
-		bannerPath = "banners/shadow.txt"
-	case "thinkertoy":
-		// User wants thinkertoy banner
-		bannerPath = "banners/thinkertoy.txt"
-	default:
-		// User provided an invalid banner name
-		// Show error message with what they typed and what's available
-		fmt.Printf("Error: Invalid banner '%s'\n", opts.Banner)
-		fmt.Println("Available banners: standard, shadow, thinkertoy")
+	// Step 3: Load the banner
+	// The registry looks the name up in the banner search path
+	// (--banner-dir, $ASCII_ART_BANNER_PATH, the user data folder and
+	// the built-in banners), or loads it directly if it's a file path;
+	// any --fallback banners are loaded too and fill in missing characters
+	registry := NewBannerRegistry(opts.BannerDirs)
+	banner, info, bannerName, err := registry.LoadChain(opts.Banner, opts.Fallbacks)
+
+	// Step 4: Check if the banner name is unknown
+	// Show error message with what they typed and what's available
+	if errors.Is(err, errUnknownBanner) {
+		fmt.Printf("Error: Invalid banner '%s'\n", bannerName)
+		fmt.Printf("Available banners: %s (or a path to a .txt/.flf/.bdf file)\n", strings.Join(registry.Names(), ", "))
 		return // Exit the program
 	}
 
-	// Step 4: Load the banner file from disk
-	// This reads the file and creates the Banner map
-	// where each character maps to its ASCII art
-	banner, err := LoadBanner(bannerPath)
-
 	// Step 5: Check if loading the banner failed
 	// Could fail if file doesn't exist, is corrupted, etc.
 	if err != nil {
-		// Show which file failed and why
-		fmt.Printf("Error: Could not load banner file '%s'\n", bannerPath)
+		// Show which banner failed and why
+		fmt.Printf("Error: Could not load banner file '%s'\n", bannerName)
 		fmt.Printf("Details: %v\n", err)
 		return // Exit the program
 	}
 
+	// Step 5b: Open the file the text is read from (--input=<file>, or
+	// stdin for - or piped text); it's read a line at a time as it's drawn
+	var input io.Reader
+	if opts.InputFile != "" {
+		file, err := openInput(opts.InputFile)
+		if err != nil {
+			fmt.Printf("Error: Could not read input file '%s'\n", opts.InputFile)
+			fmt.Printf("Details: %v\n", err)
+			return // Exit the program
+		}
+		defer file.Close()
+		input = file
+	}
+
+	// Settings for how the art is laid out (e.g. --layout=smush, --scale=2,
+	// --shear=1, --wave=2, --shadow=1,1, --direction=vertical, --transform=mirror)
+	// without --layout, the banner's own default layout (if any) is used
+	renderOpts := RenderOptions{
+		Layout:     opts.Layout,
+		Scale:      opts.Scale,
+		Shear:      opts.Shear,
+		Wave:       opts.Wave,
+		Shadow:     opts.Shadow,
+		Direction:  opts.Direction,
+		Spacing:    opts.Spacing,
+		Align:      opts.Align,
+		Width:      opts.Width,
+		Transforms: opts.Transforms,
+	}
+	if !opts.LayoutProvided && info.Layout != "" {
+		renderOpts.Layout, _ = ParseLayout(info.Layout)
+	}
+
+	// Without --width, long lines are wrapped to fit the terminal
+	// (only when printing to one, not into a file or a pipe)
+	if !opts.WidthProvided && opts.OutputFile == "" && isTerminal(os.Stdout) {
+		renderOpts.Width = terminalWidth(os.Stdout)
+	}
+
 	// Step 6: Decide whether to render with color or without color
 	// This is the main branching point in our program
 	if opts.UseColor {
@@ -87,18 +125,9 @@ func main() {
 		if opts.SubstringArgProvided && opts.Substring == "" {
 			fmt.Println("Warning: Empty substring provided. Rendering without color.")
 			fmt.Println()
-			output := RenderInput(opts.Text, banner)
-			if opts.OutputFile != "" {
-				// Write to file
-				err := os.WriteFile(opts.OutputFile, []byte(output), 0644)
-				if err != nil {
-					fmt.Printf("Error writing to file: %v\n", err)
-					return
-				}
-			} else {
-				// Write to screen
-				fmt.Print(output)
-			}
+			writeOutput(opts.OutputFile, func(w io.Writer) error {
+				return renderText(w, opts, input, banner, "", nil, renderOpts)
+			})
 			return
 		}
 
@@ -108,47 +137,72 @@ func main() {
 		// If substring is empty (no substring arg), it returns ALL positions (color everything)
 		indexes := FindSubstringIndexes(opts.Text, opts.Substring)
 
-		// Step 6e: Render the text with colors
-		// This is our NEW function that we created
+		// Step 6e: Render the text with colors, straight to the screen
+		// or the --output file (a line at a time, so long texts don't
+		// need to fit in memory)
 		// It renders character-by-character and adds color codes where needed
-		output := RenderWithColor(opts.Text, banner, colorCode, indexes)
-
-		// Step 6f: Print the colored output
-		if opts.OutputFile != "" {
-			// Write to file
-			err := os.WriteFile(opts.OutputFile, []byte(output), 0644)
-			if err != nil {
-				fmt.Printf("Error writing to file: %v\n", err)
-				return
-			}
-		} else {
-			// Write to screen
-			fmt.Print(output)
-		}
+		writeOutput(opts.OutputFile, func(w io.Writer) error {
+			return renderText(w, opts, input, banner, colorCode, indexes, renderOpts)
+		})
 
 	} else {
 		// ===== NORMAL MODE (NO COLOR) =====
 		// User didn't specify --color flag
 		// Render normally, just like the old ascii-art program
 
-		// Step 6g: Render the text without colors
-		// This is our existing function from before
-		output := RenderInput(opts.Text, banner)
-
-		// Step 6h: Print the normal output
-		if opts.OutputFile != "" {
-			// Write to file
-			err := os.WriteFile(opts.OutputFile, []byte(output), 0644)
-			if err != nil {
-				fmt.Printf("Error writing to file: %v\n", err)
-				return
-			}
-		} else {
-			// Write to screen
-			fmt.Print(output)
-		}
+		// Step 6f: Render the text without colors
+		// This is our existing function from before, writing as it goes
+		writeOutput(opts.OutputFile, func(w io.Writer) error {
+			return renderText(w, opts, input, banner, "", nil, renderOpts)
+		})
 	}
 
 	// Program ends successfully
 	// No need for explicit return at the end of main
 }
+
+// renderText draws the text from the arguments, or if there is an input
+// (a file or stdin), the text read from it; as that text isn't known
+// up front, the substring is found in each line as it's read
+func renderText(w io.Writer, opts ColorOptions, input io.Reader, banner Banner, colorCode string, indexes []int, renderOpts RenderOptions) error {
+	if input != nil {
+		return RenderFrom(w, input, banner, colorCode, opts.Substring, renderOpts)
+	}
+	// the escapes in the text were decoded with the arguments
+	return renderLines(w, opts.Text, banner, colorCode, indexes, renderOpts)
+}
+
+// writeOutput runs render with where the art should go: the --output file
+// if there is one, otherwise the screen
+func writeOutput(outputFile string, render func(w io.Writer) error) {
+	var w io.Writer = os.Stdout
+	var file *os.File
+	if outputFile != "" {
+		var err error
+		file, err = os.OpenFile(outputFile, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644)
+		if err != nil {
+			fmt.Printf("Error writing to file: %v\n", err)
+			return
+		}
+		w = file
+	}
+
+	// buffered, so the art isn't written a few characters at a time
+	out := bufio.NewWriter(w)
+	err := render(out)
+	if flushErr := out.Flush(); err == nil {
+		err = flushErr
+	}
+	// closing can fail too (e.g. a full disk or a network drive), and then
+	// the file isn't complete
+	if file != nil {
+		if closeErr := file.Close(); err == nil {
+			err = closeErr
+		}
+	}
+	if err != nil && outputFile != "" {
+		fmt.Printf("Error writing to file: %v\n", err)
+	} else if err != nil {
+		fmt.Fprintf(os.Stderr, "Error writing output: %v\n", err)
+	}
+}
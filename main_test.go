@@ -2,6 +2,9 @@ package main
 
 import (
 	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -160,3 +163,131 @@ func TestRenderInputEmpty(t *testing.T) {
 		t.Errorf("RenderInput(\"\") = %q, want empty string", got)
 	}
 }
+
+// test that a banner with 5-line characters is detected as 5 tall
+func TestLoadBannerDetectsHeight(t *testing.T) {
+	// build a banner file: every char is 1 empty line + 5 art lines
+	var content strings.Builder
+	for c := firstChar; c <= lastChar; c++ {
+		content.WriteString("\n")
+		for row := 0; row < 5; row++ {
+			content.WriteString(fmt.Sprintf("%c%d\n", c, row))
+		}
+	}
+	path := filepath.Join(t.TempDir(), "compact.txt")
+	if err := os.WriteFile(path, []byte(content.String()), 0644); err != nil {
+		t.Fatalf("failed to write banner: %v", err)
+	}
+
+	banner, err := LoadBanner(path)
+	if err != nil {
+		t.Fatalf("failed to load banner: %v", err)
+	}
+	if banner.Height() != 5 {
+		t.Errorf("expected height 5, got %d", banner.Height())
+	}
+
+	// rendering should produce 5 rows, not 8
+	got := RenderLine("A", banner)
+	want := "A0\nA1\nA2\nA3\nA4\n"
+	if got != want {
+		t.Errorf("RenderLine(\"A\") = %q, want %q", got, want)
+	}
+}
+
+// test that the bundled banners are embedded in the binary
+func TestLoadBannerFSEmbedded(t *testing.T) {
+	for _, name := range []string{"standard", "shadow", "thinkertoy"} {
+		banner, err := LoadBannerFS(builtinBanners, "banners/"+name+".txt")
+		if err != nil {
+			t.Errorf("failed to load embedded %s: %v", name, err)
+			continue
+		}
+		if len(banner) != 95 {
+			t.Errorf("embedded %s: expected 95 characters, got %d", name, len(banner))
+		}
+	}
+}
+
+// test that tagged blocks after '~' add characters beyond ASCII
+func TestLoadBannerUnicodeBlocks(t *testing.T) {
+	data, err := builtinBanners.ReadFile("banners/standard.txt")
+	if err != nil {
+		t.Fatalf("failed to read banner: %v", err)
+	}
+
+	// add Greek omega (U+03A9) and e-acute (U+00E9) after the ASCII blocks
+	content := string(data)
+	for _, tag := range []string{"U+03A9 GREEK CAPITAL LETTER OMEGA", "u+00e9"} {
+		content += tag + "\n"
+		for row := 0; row < charHeight; row++ {
+			content += fmt.Sprintf("%s%d\n", tag[:6], row)
+		}
+	}
+	path := filepath.Join(t.TempDir(), "greek.txt")
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write banner: %v", err)
+	}
+
+	banner, err := LoadBanner(path)
+	if err != nil {
+		t.Fatalf("failed to load banner: %v", err)
+	}
+	if banner.Height() != charHeight {
+		t.Errorf("expected height %d, got %d", charHeight, banner.Height())
+	}
+	if banner['Ω'][0] != "U+03A90" {
+		t.Errorf("expected Ω glyph, got %q", banner['Ω'])
+	}
+	if banner['é'][7] != "u+00e97" {
+		t.Errorf("expected é glyph, got %q", banner['é'])
+	}
+}
+
+// test that the bundled banners pass validation
+func TestValidateBannerBundled(t *testing.T) {
+	for _, name := range []string{"standard", "shadow", "thinkertoy"} {
+		issues, err := ValidateBanner("banners/" + name + ".txt")
+		if err != nil {
+			t.Fatalf("failed to read %s: %v", name, err)
+		}
+		if len(issues) != 0 {
+			t.Errorf("%s: expected no problems, got %v", name, issues)
+		}
+	}
+}
+
+// test that ragged rows, tabs and duplicates are reported with line numbers
+func TestValidateBannerProblems(t *testing.T) {
+	data, err := builtinBanners.ReadFile("banners/standard.txt")
+	if err != nil {
+		t.Fatalf("failed to read banner: %v", err)
+	}
+	lines := strings.Split(string(data), "\n")
+
+	// line 12 is row 2 of '!': make it wider and add a tab
+	lines[11] += "\t"
+	content := strings.Join(lines, "\n")
+	// define 'A' a second time as a tagged block
+	content += "U+0041\n" + strings.Repeat("A\n", charHeight)
+
+	issues := validateBannerData([]byte(content))
+
+	want := map[string]bool{
+		"line 12: '!': non-printable character '\\t' at column 5":  false,
+		"line 12: '!': row 2 is 5 wide, the other rows are 4":      false,
+		"line 856: 'A': defined again (first defined on line 298)": false,
+	}
+	for _, issue := range issues {
+		if _, ok := want[issue.String()]; ok {
+			want[issue.String()] = true
+		} else {
+			t.Errorf("unexpected problem: %s", issue)
+		}
+	}
+	for msg, found := range want {
+		if !found {
+			t.Errorf("expected problem: %s", msg)
+		}
+	}
+}
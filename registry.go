@@ -0,0 +1,230 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// bannerPathEnv lists extra banner folders, separated like $PATH
+const bannerPathEnv = "ASCII_ART_BANNER_PATH"
+
+// errUnknownBanner is returned when no folder in the search path has the banner
+var errUnknownBanner = errors.New("unknown banner")
+
+// bannerSource is one folder that banners are looked up in
+type bannerSource struct {
+	label string // shown in messages (a directory path or "built-in")
+	fsys  fs.FS
+	dir   string // folder inside fsys that holds the banner files
+}
+
+// BannerRegistry finds banners by name in an ordered list of folders
+// the first folder that has <name>.txt, <name>.flf or <name>.bdf wins
+type BannerRegistry struct {
+	sources []bannerSource
+}
+
+// NewBannerRegistry builds the banner search path, highest priority first:
+//   - the extra dirs given (from --banner-dir)
+//   - the folders listed in $ASCII_ART_BANNER_PATH
+//   - $XDG_DATA_HOME/ascii-art/banners (~/.local/share/ascii-art/banners)
+//   - a banners folder in the current directory (overrides the built-ins)
+//   - the built-in banners embedded in the binary
+func NewBannerRegistry(extraDirs []string) *BannerRegistry {
+	r := &BannerRegistry{}
+
+	for _, dir := range extraDirs {
+		r.addDir(dir)
+	}
+	for _, dir := range filepath.SplitList(os.Getenv(bannerPathEnv)) {
+		if dir != "" {
+			r.addDir(dir)
+		}
+	}
+	if dataDir := xdgDataHome(); dataDir != "" {
+		r.addDir(filepath.Join(dataDir, "ascii-art", "banners"))
+	}
+	r.addDir("banners")
+
+	r.sources = append(r.sources, bannerSource{label: "built-in", fsys: builtinBanners, dir: "banners"})
+	return r
+}
+
+// addDir adds a folder on disk to the end of the search path
+func (r *BannerRegistry) addDir(dir string) {
+	r.sources = append(r.sources, bannerSource{label: dir, fsys: os.DirFS(dir), dir: "."})
+}
+
+// xdgDataHome returns $XDG_DATA_HOME, or its default ~/.local/share
+func xdgDataHome() string {
+	if dir := os.Getenv("XDG_DATA_HOME"); dir != "" {
+		return dir
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".local", "share")
+}
+
+// isBannerFile reports whether a file name looks like a banner we can load
+func isBannerFile(name string) bool {
+	return strings.HasSuffix(name, ".txt") || isFIGletPath(name) || isBDFPath(name)
+}
+
+// isBannerPath reports whether a banner argument is a path to a file
+// rather than a name to look up (e.g. fonts/big.flf or ./my.txt)
+func isBannerPath(name string) bool {
+	return strings.ContainsRune(name, '/') || strings.ContainsRune(name, filepath.Separator) || isFIGletPath(name) || isBDFPath(name)
+}
+
+// find returns the source and file name for a banner name
+func (r *BannerRegistry) find(name string) (bannerSource, string, bool) {
+	for _, src := range r.sources {
+		for _, ext := range []string{".txt", ".flf", ".bdf"} {
+			file := path.Join(src.dir, name+ext)
+			if info, err := fs.Stat(src.fsys, file); err == nil && !info.IsDir() {
+				return src, file, true
+			}
+		}
+	}
+	return bannerSource{}, "", false
+}
+
+// Has reports whether name can be loaded, either as a banner name
+// in the search path or as a path to a banner file
+// (with or without modifiers like +bold)
+func (r *BannerRegistry) Has(name string) bool {
+	name, _ = splitBannerVariants(name)
+	if name == "" {
+		return false
+	}
+	if isBannerPath(name) {
+		info, err := os.Stat(name)
+		return err == nil && !info.IsDir()
+	}
+	_, _, ok := r.find(name)
+	return ok
+}
+
+// Load loads a banner by name (or by path to a .txt/.flf/.bdf file)
+func (r *BannerRegistry) Load(name string) (Banner, error) {
+	banner, _, err := r.LoadInfo(name)
+	return banner, err
+}
+
+// LoadInfo is like Load but also returns the banner's info
+// a banner without a name in its header is named after its file
+// modifiers after the name (standard+bold) are applied to the loaded banner
+func (r *BannerRegistry) LoadInfo(name string) (Banner, BannerInfo, error) {
+	base, variants := splitBannerVariants(name)
+	data, where, err := r.ReadFile(base)
+	if err != nil {
+		return nil, BannerInfo{}, err
+	}
+	banner, info, err := parseBannerFile(where, data)
+	if err != nil {
+		return nil, BannerInfo{}, fmt.Errorf("%s (%s): %w", name, where, err)
+	}
+	if info.Name == "" {
+		info.Name = strings.TrimSuffix(filepath.Base(base), filepath.Ext(base))
+	}
+	for _, variant := range variants {
+		banner = bannerVariants[variant](banner, info.Ink)
+		info.Name += "+" + variant
+	}
+	return banner, info, nil
+}
+
+// parseBannerFile picks the loader for a banner file by its extension
+func parseBannerFile(name string, data []byte) (Banner, BannerInfo, error) {
+	switch {
+	case isFIGletPath(name):
+		return parseFIGletInfo(string(data))
+	case isBDFPath(name):
+		return parseBDFInfo(string(data), defaultInk)
+	default:
+		return parseBannerInfo(data)
+	}
+}
+
+// Names lists every banner name found in the search path, sorted
+func (r *BannerRegistry) Names() []string {
+	seen := make(map[string]bool)
+	var names []string
+
+	for _, src := range r.sources {
+		entries, err := fs.ReadDir(src.fsys, src.dir)
+		if err != nil {
+			// missing folders are normal (e.g. no user banners yet)
+			continue
+		}
+		for _, entry := range entries {
+			if entry.IsDir() || !isBannerFile(entry.Name()) {
+				continue
+			}
+			name := strings.TrimSuffix(entry.Name(), path.Ext(entry.Name()))
+			if !seen[name] {
+				seen[name] = true
+				names = append(names, name)
+			}
+		}
+	}
+
+	sort.Strings(names)
+	return names
+}
+
+// ReadFile returns the raw contents of a banner file and where it was found
+// (used by tools like validate that look at the file rather than the glyphs)
+// modifiers like +bold are ignored, the file is the plain banner's
+func (r *BannerRegistry) ReadFile(name string) ([]byte, string, error) {
+	name, _ = splitBannerVariants(name)
+	if isBannerPath(name) {
+		data, err := os.ReadFile(name)
+		return data, name, err
+	}
+
+	src, file, ok := r.find(name)
+	if !ok {
+		return nil, "", fmt.Errorf("%w %q", errUnknownBanner, name)
+	}
+	data, err := fs.ReadFile(src.fsys, file)
+	if src.dir == "." {
+		file = filepath.Join(src.label, file)
+	} else {
+		file = src.label + " " + file
+	}
+	return data, file, err
+}
+
+// LoadChain loads a banner plus its fallback banners and combines them
+// with WithFallback (lining up baselines where the banners declare them)
+// it returns the main banner's info, and if loading fails, which name failed
+func (r *BannerRegistry) LoadChain(name string, fallbacks []string) (Banner, BannerInfo, string, error) {
+	banner, info, err := r.LoadInfo(name)
+	if err != nil {
+		return nil, info, name, err
+	}
+	if len(fallbacks) == 0 {
+		return banner, info, name, nil
+	}
+
+	chain := make([]Banner, 0, len(fallbacks))
+	baselines := make([]int, 0, len(fallbacks))
+	for _, fallbackName := range fallbacks {
+		fallback, fallbackInfo, err := r.LoadInfo(fallbackName)
+		if err != nil {
+			return nil, info, fallbackName, err
+		}
+		chain = append(chain, fallback)
+		baselines = append(baselines, fallbackInfo.Baseline)
+	}
+	return withFallbackAligned(banner, info.Baseline, chain, baselines), info, name, nil
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// copyStandardTo writes the bundled standard banner into dir under a new name
+func copyStandardTo(t *testing.T, dir, name string) {
+	data, err := builtinBanners.ReadFile("banners/standard.txt")
+	if err != nil {
+		t.Fatalf("failed to read embedded banner: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, name+".txt"), data, 0644); err != nil {
+		t.Fatalf("failed to write banner: %v", err)
+	}
+}
+
+// test that the built-in banners are always available
+func TestRegistryBuiltins(t *testing.T) {
+	t.Setenv(bannerPathEnv, "")
+	registry := NewBannerRegistry(nil)
+
+	for _, name := range []string{"standard", "shadow", "thinkertoy"} {
+		if !registry.Has(name) {
+			t.Errorf("expected built-in banner %q", name)
+		}
+	}
+	if registry.Has("nope") {
+		t.Error("did not expect banner \"nope\"")
+	}
+}
+
+// test that a banner dropped into a --banner-dir folder is usable by name
+func TestRegistryBannerDir(t *testing.T) {
+	dir := t.TempDir()
+	copyStandardTo(t, dir, "mine")
+
+	registry := NewBannerRegistry([]string{dir})
+	banner, err := registry.Load("mine")
+	if err != nil {
+		t.Fatalf("failed to load banner: %v", err)
+	}
+	if len(banner) != 95 {
+		t.Errorf("expected 95 characters, got %d", len(banner))
+	}
+
+	found := false
+	for _, name := range registry.Names() {
+		if name == "mine" {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("expected \"mine\" in %v", registry.Names())
+	}
+}
+
+// test that folders listed in the environment variable are searched
+func TestRegistryEnvPath(t *testing.T) {
+	dir := t.TempDir()
+	copyStandardTo(t, dir, "fromenv")
+	t.Setenv(bannerPathEnv, dir)
+
+	if !NewBannerRegistry(nil).Has("fromenv") {
+		t.Error("expected banner from environment path")
+	}
+}
+
+// test that ParseColorArgs recognises banners from the search path
+func TestParseColorArgs_CustomBanner(t *testing.T) {
+	dir := t.TempDir()
+	copyStandardTo(t, dir, "poster")
+
+	args := []string{"program", "--banner-dir=" + dir, "Hello", "poster"}
+	opts, err := ParseColorArgs(args)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if opts.Banner != "poster" {
+		t.Errorf("Expected banner 'poster', got '%s'", opts.Banner)
+	}
+}
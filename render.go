@@ -1,102 +1,133 @@
 package main
 
-import "strings"
+import (
+	"io"
+	"slices"
+	"strings"
+)
+
+// RenderOptions are the settings for one render
+// the zero value renders the classic way (characters side by side)
+type RenderOptions struct {
+	Layout Layout // how neighbouring characters are joined (full, fit, smush)
+	Scale  Scale  // how many times bigger each character is drawn (e.g. 2x2)
+	Shear  int    // columns each row leans right of the row below (italics)
+	Wave   Wave   // moves the columns up and down along a wave
+	Shadow Shadow // a drop shadow or 3D depth behind the ink
+
+	// Direction is which way the characters go; in vertical text they are
+	// stacked Spacing blank rows apart
+	Direction Direction
+	Spacing   int
+
+	// Width wraps lines that would be wider than this many columns,
+	// between words where possible (0 means no wrapping)
+	// Align lines each line up within Width (or the widest line); in
+	// vertical text it places each character in its column (justify is
+	// for lines of words only, in a column it's the same as left)
+	Width int
+	Align Align
+
+	// Transforms are applied to the finished block, in order
+	Transforms []Transform
+}
 
 // RenderLine takes a string and makes ASCII art from it
-// builds it row by row (each character is 8 rows tall)
+// builds it row by row (each character is b.Height() rows tall)
 func RenderLine(s string, b Banner) string {
-	const height = charHeight
+	return renderLineGrid([]rune(s), b, "", nil, RenderOptions{}).String()
+}
 
-	var builder strings.Builder
+// RenderLineOptions is RenderLine with render options, e.g. a right-to-left
+// direction draws the first character on the right
+func RenderLineOptions(s string, b Banner, opts RenderOptions) string {
+	return renderLineGrid([]rune(s), b, "", nil, opts).String()
+}
+
+// renderLineGrid lays out one line of text (no newlines) as a grid of cells
+// characters whose position is in indexes are drawn in colorCode
+func renderLineGrid(chars []rune, b Banner, colorCode string, indexes []int, opts RenderOptions) grid {
+	height := b.Height()
+
+	// one row of cells for each row of the characters (0 to height-1)
+	rows := make(grid, height)
 
 	// make empty glyph for characters we don't have
 	empty := make([]string, height)
 
-	// go through each row (0 to 7)
-	for row := 0; row < height; row++ {
-		// for each character in the input string
-		for _, ch := range s {
-			glyph, ok := b[ch]
-			if !ok {
-				// character not in banner, use empty space
-				glyph = empty
+	// the order the characters are drawn in, left to right; right-to-left
+	// text is reordered, but charIndex stays the character's position in
+	// the text so the colors (from FindSubstringIndexes) still match
+	order := make([]int, len(chars))
+	for i := range order {
+		order[i] = i
+	}
+	var levels []int
+	switch opts.Direction {
+	case DirectionRTL:
+		// every character right to left, even Latin letters
+		slices.Reverse(order)
+		levels = make([]int, len(chars))
+		for i := range levels {
+			levels[i] = 1
+		}
+	case DirectionAuto:
+		// bidi order: words in right-to-left scripts are reversed, numbers
+		// and Latin words inside them still read left to right
+		order, levels = bidiOrder(chars, startsRTL(chars))
+	}
+
+	prevWidth := 0
+	for _, charIndex := range order {
+		ch := chars[charIndex]
+		if levels != nil && levels[charIndex]%2 == 1 {
+			// brackets inside right-to-left text face the other way
+			if mirrored, ok := bidiMirrors[ch]; ok {
+				ch = mirrored
 			}
-			// add this character's row to the line
-			builder.WriteString(glyph[row])
 		}
-		// end of row, add newline
-		builder.WriteRune('\n')
+
+		glyph, ok := b.Glyph(ch)
+		if !ok {
+			// character not in banner, use empty space
+			glyph = empty
+		}
+
+		// only the characters at the given positions get the color
+		color := ""
+		if colorCode != "" && ContainsIndex(indexes, charIndex) {
+			color = colorCode
+		}
+
+		// add this character's rows to the line, joined the way the layout says
+		width := glyphWidth(glyph)
+		opts.Layout.addGlyph(rows, glyphCells(glyph, height, color, charIndex), prevWidth, width)
+		prevWidth = width
 	}
 
-	return builder.String()
+	return rows
 }
 
 // decodeEscapedNewlines converts \n to actual newlines
 // when user types "Hello\nWorld" we want real newlines
+// the other escapes (\t, \\, \xHH, \uXXXX) are decoded too, see
+// DecodeEscapes; sequences that aren't valid are left as they are
 func decodeEscapedNewlines(s string) string {
-	runes := []rune(s)
-	out := make([]rune, 0, len(runes))
-
-	// go through each character
-	for i := 0; i < len(runes); i++ {
-		// if we see \ followed by n, make it a real newline
-		if runes[i] == '\\' && i+1 < len(runes) && runes[i+1] == 'n' {
-			out = append(out, '\n')
-			i++ // skip the n since we already used it
-		} else {
-			// normal character, just copy it
-			out = append(out, runes[i])
-		}
-	}
-
-	return string(out)
+	decoded, _ := DecodeEscapes(s)
+	return decoded
 }
 
 // RenderInput is the main function that handles everything
 // takes user input and converts it to ASCII art
 func RenderInput(input string, b Banner) string {
-	// first convert \n strings to real newlines
-	input = decodeEscapedNewlines(input)
-
-	// if input is empty, return empty
-	if input == "" {
-		return ""
-	}
-
-	// if no newlines, just render as single line
-	if !strings.Contains(input, "\n") {
-		return RenderLine(input, b)
-	}
-
-	var builder strings.Builder
-
-	// split input by newlines to handle multiple lines
-	parts := strings.Split(input, "\n")
-	hadText := false
-
-	// process each line
-	for i, part := range parts {
-		last := i == len(parts)-1
-
-		if part != "" {
-			// non-empty line, render it
-			asciiBlock := RenderLine(part, b)
-			builder.WriteString(asciiBlock)
-			hadText = true
-
-		} else {
-			// empty line handling
-			if !last {
-				// empty line in middle, add blank line
-				builder.WriteRune('\n')
-			} else if hadText {
-				// empty line at end, keep the newline
-				builder.WriteRune('\n')
-			}
-		}
-	}
+	return RenderInputOptions(input, b, RenderOptions{})
+}
 
-	return builder.String()
+// RenderInputOptions is RenderInput with render options (like the layout)
+// plain output is colored output with nothing to color, so it shares
+// the same code as RenderWithColor
+func RenderInputOptions(input string, b Banner, opts RenderOptions) string {
+	return RenderWithColorOptions(input, b, "", nil, opts)
 }
 
 // RenderWithColor renders text with specific characters colored
@@ -110,134 +141,64 @@ func RenderInput(input string, b Banner) string {
 //
 // Returns: the colored ASCII art as a string
 func RenderWithColor(input string, banner Banner, colorCode string, indexes []int) string {
+	return RenderWithColorOptions(input, banner, colorCode, indexes, RenderOptions{})
+}
+
+// RenderWithColorOptions is RenderWithColor with render options (like the layout)
+func RenderWithColorOptions(input string, banner Banner, colorCode string, indexes []int, opts RenderOptions) string {
+	var builder strings.Builder
+	RenderTo(&builder, input, banner, colorCode, indexes, opts)
+	return builder.String()
+}
+
+// RenderTo is RenderWithColorOptions that writes the art to w as it goes,
+// a line at a time, instead of building one big string
+func RenderTo(w io.Writer, input string, banner Banner, colorCode string, indexes []int, opts RenderOptions) error {
 	// Step 1: Decode any \n escape sequences to real newlines
 	// Example: "Hello\nWorld" becomes actual two lines
 	input = decodeEscapedNewlines(input)
 
+	return renderLines(w, input, banner, colorCode, indexes, opts)
+}
+
+// renderLines is RenderTo for text whose escapes are already decoded
+// (or shouldn't be, with --no-escapes)
+func renderLines(w io.Writer, input string, banner Banner, colorCode string, indexes []int, opts RenderOptions) error {
 	// Step 2: Handle empty input
 	// If the input is empty, there's nothing to render
 	if input == "" {
-		return ""
+		return nil
 	}
 
-	// Step 3: Check if input contains newlines
-	// If it does, we need to handle it differently (split by lines)
-	if strings.Contains(input, "\n") {
-		// Handle multi-line input with color
-		return renderMultiLineWithColor(input, banner, colorCode, indexes)
-	}
+	// Step 3: Render the lines one by one
+	// (each character's rows get the color if its position is in indexes)
+	renderer := NewRenderer(w, banner, opts)
+	renderer.Color = colorCode
+	renderer.Indexes = indexes
+	renderer.WriteText(input)
 
-	// Step 4: Single line rendering (most common case)
-	// This is where we do the character-by-character coloring
-	return renderSingleLineWithColor(input, banner, colorCode, indexes)
+	// Step 4: Finish off (the last blank line, and anything that
+	// needed all the lines first, like --transform=flip)
+	return renderer.Close()
 }
 
 // renderSingleLineWithColor renders a single line with colors
-// This is the core coloring logic - character by character
-func renderSingleLineWithColor(input string, banner Banner, colorCode string, indexes []int) string {
-	// Constants
-	const height = charHeight // 8 lines per character
-
-	// Create a string builder for efficient string concatenation
-	var builder strings.Builder
-
-	// Create an empty glyph for unknown characters
-	empty := make([]string, height)
-
+// This is the core coloring logic - character by character:
+// each character's rows are drawn in the color if its position is in
+// indexes, and the layout decides how the characters are joined
+func renderSingleLineWithColor(input string, banner Banner, colorCode string, indexes []int, opts RenderOptions) grid {
 	// Convert input string to slice of runes (characters)
 	// This handles Unicode properly
 	chars := []rune(input)
 
-	// Step 1: Render row by row (each character is 8 rows tall)
-	// We go through rows 0 to 7
-	for row := 0; row < height; row++ {
-		// Step 2: For each character in the input
-		for charIndex, ch := range chars {
-			// Step 3: Get the ASCII art for this character from the banner
-			glyph, ok := banner[ch]
-			if !ok {
-				// Character not found in banner, use empty glyph
-				glyph = empty
-			}
-
-			// Step 4: Check if this character needs to be colored
-			// We check if this character's position is in our indexes slice
-			shouldColor := ContainsIndex(indexes, charIndex)
-
-			// Step 5: Add color code if needed (at the start of each row for this char)
-			if shouldColor {
-				// Add the color code BEFORE this character's row
-				builder.WriteString(colorCode)
-				// Add this character's current row
-				builder.WriteString(glyph[row])
-				// Add the reset code AFTER this character's row
-				builder.WriteString(ResetColor)
-			} else {
-				// No color needed, just add the row normally
-				builder.WriteString(glyph[row])
-			}
-		}
-
-		// Step 6: End of this row, add newline
-		builder.WriteRune('\n')
-	}
-
-	// Return the complete colored ASCII art
-	return builder.String()
-}
-
-// renderMultiLineWithColor handles input with newlines
-// Splits the input by newlines and renders each part
-func renderMultiLineWithColor(input string, banner Banner, colorCode string, indexes []int) string {
-	var builder strings.Builder
-
-	// Split input by newlines
-	lines := strings.Split(input, "\n")
-	hadText := false
-
-	// Keep track of character position across all lines
-	// This is important for correct coloring when we have multiple lines
-	totalPos := 0
-
-	// Process each line
-	for i, line := range lines {
-		isLast := i == len(lines)-1
-
-		if line != "" {
-			// Non-empty line - render it with color
-
-			// Calculate which indexes apply to THIS line
-			// We need to adjust indexes based on totalPos
-			lineIndexes := make([]int, 0)
-			for _, idx := range indexes {
-				// Check if this index falls within this line
-				if idx >= totalPos && idx < totalPos+len(line) {
-					// Adjust index to be relative to this line
-					lineIndexes = append(lineIndexes, idx-totalPos)
-				}
-			}
+	// Lay the characters out as rows of cells,
+	// then lean or wave them if asked to (e.g. --shear=1)
+	rows := opts.distort(renderLineGrid(chars, banner, colorCode, indexes, opts))
 
-			// Render this line with its adjusted indexes
-			asciiBlock := renderSingleLineWithColor(line, banner, colorCode, lineIndexes)
-			builder.WriteString(asciiBlock)
-			hadText = true
-
-			// Update total position (including the newline character)
-			totalPos += len(line) + 1
-
-		} else {
-			// Empty line handling
-			if !isLast {
-				// Empty line in the middle, add a blank line
-				builder.WriteRune('\n')
-			} else if hadText {
-				// Empty line at the end, keep the newline
-				builder.WriteRune('\n')
-			}
-			// Update position for the newline
-			totalPos++
-		}
+	// right-to-left lines end on the right, so every row is made
+	// as wide as the widest one
+	if opts.Direction.lineIsRTL(chars) {
+		rows = rows.padded()
 	}
-
-	return builder.String()
+	return rows
 }
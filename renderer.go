@@ -0,0 +1,247 @@
+package main
+
+import (
+	"bufio"
+	"io"
+	"strings"
+	"unicode/utf8"
+)
+
+// Renderer draws text as ASCII art straight to a writer, one line at a
+// time, so only one line of output is held in memory however long the
+// text is; RenderInput and RenderWithColor use one too
+//
+// some settings need all of the text before anything can be drawn:
+// transforms (a flipped block starts with its last line), vertical text,
+// and lining lines up with the widest one (--align or right-to-left text
+// without a Width); with those the output is kept until Close
+type Renderer struct {
+	// Color is the ANSI color code colored characters are drawn in
+	Color string
+
+	// Indexes are the positions of the characters to color, counted over
+	// all the text written (a newline counts as one character), like
+	// FindSubstringIndexes gives for the whole text
+	Indexes []int
+
+	// Match, if set, is used instead of Indexes: it gets each line of text
+	// and returns the positions in that line to color (for text that is
+	// read bit by bit, e.g. FindSubstringIndexes(line, substring))
+	Match func(line string) []int
+
+	w      io.Writer
+	banner Banner
+	opts   RenderOptions
+
+	pos     int  // position of the next line's first character
+	hadText bool // a line with text in it has been written
+	blank   bool // an empty line is waiting to find out if it was the last
+	err     error
+
+	partial string // text after WriteText's last newline, not ended yet
+	open    bool   // there is a partial line (maybe empty) to finish
+
+	buffer bool           // keep everything until Close (see above)
+	lines  []renderedLine // the kept lines
+	text   []string       // the kept text, for vertical text
+}
+
+// NewRenderer makes a Renderer that writes to w
+func NewRenderer(w io.Writer, banner Banner, opts RenderOptions) *Renderer {
+	return &Renderer{
+		w: w,
+		// Make the characters bigger if asked to (e.g. --scale=2)
+		// the scaled banner is taller, so every row still lines up
+		banner: ScaleBanner(banner, opts.Scale),
+		opts:   opts,
+		buffer: len(opts.Transforms) > 0 || opts.Direction == DirectionVertical ||
+			(opts.Width == 0 && (opts.Align > AlignLeft || opts.Direction.usesBidi())),
+	}
+}
+
+// WriteText renders text that may have several lines (split at "\n")
+// the last line is kept open: the next write carries on after it,
+// as if the texts were joined, and Close finishes it
+func (r *Renderer) WriteText(text string) error {
+	lines := strings.Split(text, "\n")
+	for _, line := range lines[:len(lines)-1] {
+		r.WriteLine(line)
+	}
+	r.partial += lines[len(lines)-1]
+	r.open = true
+	return r.err
+}
+
+// WriteLine renders one line of text (it shouldn't have a newline in it)
+// a line WriteText left open is finished with it
+func (r *Renderer) WriteLine(line string) error {
+	if r.open {
+		line = r.partial + line
+		r.partial, r.open = "", false
+	}
+	if r.err != nil {
+		return r.err
+	}
+	if r.opts.Direction == DirectionVertical {
+		// vertical text puts the lines side by side, so it needs them all
+		r.text = append(r.text, line)
+		return nil
+	}
+
+	// an empty line before this one wasn't the last, so it's a blank line
+	if r.blank {
+		r.emit(renderedLine{rows: grid{nil}})
+		r.blank = false
+	}
+
+	// Length in characters (runes), to match FindSubstringIndexes
+	lineLen := utf8.RuneCountInString(line)
+
+	if line == "" {
+		// Empty line handling: wait to see if it's the last line
+		// (at the end it's only a blank line if some text came before)
+		r.blank = true
+		// Update position for the newline
+		r.pos++
+		return r.err
+	}
+
+	// Non-empty line - render it with color
+	// Which of this line's characters are colored, counting from the
+	// start of the line
+	var lineIndexes []int
+	if r.Match != nil {
+		lineIndexes = r.Match(line)
+	} else {
+		for _, idx := range r.Indexes {
+			if idx >= r.pos && idx < r.pos+lineLen {
+				lineIndexes = append(lineIndexes, idx-r.pos)
+			}
+		}
+	}
+
+	// A line too wide for opts.Width is wrapped into pieces,
+	// each rendered as a line of its own
+	chars := []rune(line)
+	pieces := [][2]int{{0, lineLen}}
+	if r.opts.Width > 0 {
+		pieces = wrapLine(chars, r.banner, r.opts)
+	}
+
+	for n, piece := range pieces {
+		// Calculate which indexes apply to THIS piece
+		// We need to adjust indexes based on where it starts
+		pieceIndexes := make([]int, 0)
+		for _, idx := range lineIndexes {
+			if idx >= piece[0] && idx < piece[1] {
+				pieceIndexes = append(pieceIndexes, idx-piece[0])
+			}
+		}
+
+		// Render this piece with its adjusted indexes
+		text := string(chars[piece[0]:piece[1]])
+		asciiBlock := renderSingleLineWithColor(text, r.banner, r.Color, pieceIndexes, r.opts)
+		r.emit(renderedLine{
+			rows:    asciiBlock,
+			text:    text,
+			indexes: pieceIndexes,
+			rtl:     r.opts.Direction.lineIsRTL([]rune(text)),
+			// like in a book, the end of a wrapped line isn't justified
+			justify: len(pieces) == 1 || n < len(pieces)-1,
+		})
+	}
+	r.hadText = true
+
+	// Update total position (including the newline character)
+	r.pos += lineLen + 1
+	return r.err
+}
+
+// ReadFrom renders everything read from in, line by line
+// only one line of text is read in at a time; Windows line endings (\r\n)
+// and a UTF-8 byte order mark at the start are left out, and a newline
+// works just like \n in the text (one at the end makes a blank line)
+func (r *Renderer) ReadFrom(in io.Reader) (int64, error) {
+	reader := bufio.NewReader(in)
+	var n int64
+	for first := true; ; first = false {
+		line, err := reader.ReadString('\n')
+		n += int64(len(line))
+		if err != nil && err != io.EOF {
+			return n, err
+		}
+		if first {
+			line = strings.TrimPrefix(line, byteOrderMark)
+		}
+		line = strings.TrimSuffix(strings.TrimSuffix(line, "\n"), "\r")
+		if err == io.EOF {
+			// the text after the last newline (maybe nothing)
+			return n, r.WriteLine(line)
+		}
+		if err := r.WriteLine(line); err != nil {
+			return n, err
+		}
+	}
+}
+
+// Close finishes the art: the last blank line, and everything that was
+// kept back (see Renderer); the writer itself is not closed
+func (r *Renderer) Close() error {
+	// the line WriteText left open
+	if r.open {
+		r.WriteLine("")
+	}
+	if r.err != nil {
+		return r.err
+	}
+
+	if r.opts.Direction == DirectionVertical {
+		input := strings.Join(r.text, "\n")
+		if input == "" {
+			return nil
+		}
+		indexes := r.Indexes
+		if r.Match != nil {
+			indexes = r.Match(input)
+		}
+		// Vertical text: characters top to bottom, lines side by side
+		rows := r.opts.distort(renderVerticalGrid(input, r.banner, r.Color, indexes, r.opts))
+		r.write(rows)
+		return r.err
+	}
+
+	// Empty line at the end, keep the newline if there was text before it
+	if r.blank && r.hadText {
+		r.emit(renderedLine{rows: grid{nil}})
+	}
+	r.blank = false
+
+	if r.buffer {
+		r.write(alignLines(r.lines, r.banner, r.Color, r.opts))
+		r.lines = nil
+	}
+	return r.err
+}
+
+// emit writes a rendered line, or keeps it if everything is needed first
+func (r *Renderer) emit(line renderedLine) {
+	if r.buffer {
+		r.lines = append(r.lines, line)
+		return
+	}
+	r.write(alignLines([]renderedLine{line}, r.banner, r.Color, r.opts))
+}
+
+// write applies the transforms to a finished block and writes it out
+// (color code before each colored character's row, reset after it)
+func (r *Renderer) write(rows grid) {
+	if r.err != nil {
+		return
+	}
+	// Mirror, flip or rotate the whole block if asked to
+	// (on the cells, so every character keeps its color)
+	for _, t := range r.opts.Transforms {
+		rows = t.apply(rows)
+	}
+	_, r.err = io.WriteString(r.w, rows.String())
+}
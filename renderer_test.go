@@ -0,0 +1,109 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+// test that each line is written as soon as it's rendered
+func TestRendererStreams(t *testing.T) {
+	var out bytes.Buffer
+	r := NewRenderer(&out, wrapBanner, RenderOptions{})
+
+	r.WriteLine("ab")
+	if out.String() != "ab\n" {
+		t.Errorf("expected the first line before Close, got %q", out.String())
+	}
+	r.WriteLine("")
+	r.WriteLine("c")
+	if err := r.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	if out.String() != "ab\n\nc\n" {
+		t.Errorf("expected %q, got %q", "ab\n\nc\n", out.String())
+	}
+}
+
+// test that reading from a reader gives the same art as RenderInput
+func TestRendererReadFrom(t *testing.T) {
+	banner, err := LoadBanner("banners/standard.txt")
+	if err != nil {
+		t.Fatalf("failed to load banner: %v", err)
+	}
+
+	for _, text := range []string{"Hello", "Hello\nWorld", "Hello\n", "\n", "a\n\nb", ""} {
+		var out bytes.Buffer
+		r := NewRenderer(&out, banner, RenderOptions{})
+		if _, err := r.ReadFrom(strings.NewReader(text)); err != nil {
+			t.Fatalf("ReadFrom failed: %v", err)
+		}
+		r.Close()
+
+		if want := RenderInput(text, banner); out.String() != want {
+			t.Errorf("%q: expected %q, got %q", text, want, out.String())
+		}
+	}
+}
+
+// test coloring a stream line by line with Match
+func TestRendererMatch(t *testing.T) {
+	var out bytes.Buffer
+	r := NewRenderer(&out, wrapBanner, RenderOptions{})
+	r.Color = "\033[31m"
+	r.Match = func(line string) []int { return FindSubstringIndexes(line, "b") }
+	r.ReadFrom(strings.NewReader("ab\nba"))
+	r.Close()
+
+	want := "a\033[31mb\033[0m\n\033[31mb\033[0ma\n"
+	if out.String() != want {
+		t.Errorf("expected %q, got %q", want, out.String())
+	}
+}
+
+// test that settings which need every line wait for Close
+func TestRendererKeepsLines(t *testing.T) {
+	var out bytes.Buffer
+	r := NewRenderer(&out, wrapBanner, RenderOptions{Transforms: []Transform{TransformFlip}})
+	r.WriteText("ac\nca")
+	if out.Len() != 0 {
+		t.Errorf("expected nothing before Close, got %q", out.String())
+	}
+	r.Close()
+	if out.String() != "ca\nac\n" {
+		t.Errorf("expected %q, got %q", "ca\nac\n", out.String())
+	}
+}
+
+// test that text written in chunks renders like the whole text at once
+func TestRendererChunks(t *testing.T) {
+	var out bytes.Buffer
+	r := NewRenderer(&out, wrapBanner, RenderOptions{})
+	r.WriteText("ab")
+	r.WriteText("cd")
+	r.Close()
+	if out.String() != "abcd\n" {
+		t.Errorf("expected the chunks joined into %q, got %q", "abcd\n", out.String())
+	}
+
+	banner, err := LoadBanner("banners/standard.txt")
+	if err != nil {
+		t.Fatalf("failed to load banner: %v", err)
+	}
+	for _, text := range []string{"ab\ncd", "Hi\n\nthere\n", "\nHi"} {
+		want := RenderWithColor(text, banner, "\033[31m", FindSubstringIndexes(text, "i"))
+		// split the text at every place
+		for i := 0; i <= len(text); i++ {
+			var out bytes.Buffer
+			r := NewRenderer(&out, banner, RenderOptions{})
+			r.Color = "\033[31m"
+			r.Indexes = FindSubstringIndexes(text, "i")
+			r.WriteText(text[:i])
+			r.WriteText(text[i:])
+			r.Close()
+			if out.String() != want {
+				t.Errorf("%q split at %d: expected %q, got %q", text, i, want, out.String())
+			}
+		}
+	}
+}
@@ -0,0 +1,10 @@
+//go:build !linux && !darwin
+
+package main
+
+import "os"
+
+// ioctlWidth can't ask the terminal on this system; only $COLUMNS is used
+func ioctlWidth(f *os.File) int {
+	return 0
+}
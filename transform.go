@@ -0,0 +1,200 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Transform is a change to the whole rendered block, like a mirror image
+type Transform int
+
+const (
+	TransformMirror    Transform = iota // left to right
+	TransformFlip                       // upside down
+	TransformRotate90                   // a quarter turn clockwise
+	TransformRotate180                  // a half turn
+	TransformRotate270                  // a quarter turn anticlockwise
+)
+
+// transformNames are the names used by --transform
+var transformNames = map[string]Transform{
+	"mirror":    TransformMirror,
+	"flip":      TransformFlip,
+	"rotate90":  TransformRotate90,
+	"rotate180": TransformRotate180,
+	"rotate270": TransformRotate270,
+}
+
+// ParseTransforms reads a --transform value: one or more transform
+// names separated by commas, applied in that order (e.g. mirror,rotate90)
+func ParseTransforms(value string) ([]Transform, error) {
+	var transforms []Transform
+	for _, name := range strings.Split(value, ",") {
+		t, ok := transformNames[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown transform %q (expected mirror, flip, rotate90, rotate180 or rotate270)", name)
+		}
+		transforms = append(transforms, t)
+	}
+	return transforms, nil
+}
+
+// swapPairs makes a map where each pair of characters turns into the other
+func swapPairs(pairs ...string) map[rune]rune {
+	swaps := make(map[rune]rune)
+	for _, pair := range pairs {
+		p := []rune(pair)
+		swaps[p[0]], swaps[p[1]] = p[1], p[0]
+	}
+	return swaps
+}
+
+// Characters that look different once the art is turned around, and what
+// to draw instead so lines still join up
+var (
+	mirrorSwaps = swapPairs(`/\`, "()", "[]", "{}", "<>", "bd", "pq")
+	flipSwaps   = swapPairs("_‾", `/\`, "^v", "'.", "bp", "dq", "MW")
+
+	// a quarter turn makes lines across into lines down and the other way
+	// round; the arrows point a quarter turn further on
+	rotate90Swaps = map[rune]rune{
+		'|': '-', '-': '|', '_': '|', '‾': '|', '/': '\\', '\\': '/',
+		'<': '^', '^': '>', '>': 'v', 'v': '<',
+	}
+	rotate270Swaps = map[rune]rune{
+		'|': '-', '-': '|', '_': '|', '‾': '|', '/': '\\', '\\': '/',
+		'<': 'v', 'v': '>', '>': '^', '^': '<',
+	}
+)
+
+// swapped returns the character to draw for ch after a transform
+func swapped(c cell, swaps map[rune]rune) cell {
+	if to, ok := swaps[c.ch]; ok {
+		c.ch = to
+	}
+	return c
+}
+
+// padded makes every row of a grid as wide as the widest one, so the block
+// keeps its shape when it's turned around
+func (g grid) padded() grid {
+	width := 0
+	for _, row := range g {
+		width = max(width, len(row))
+	}
+	out := make(grid, len(g))
+	for r, row := range g {
+		out[r] = append(append([]cell{}, row...), blankCells(width-len(row))...)
+	}
+	return out
+}
+
+// apply transforms a rendered grid; the cells keep their colors, so the
+// colored characters stay colored wherever they end up
+func (t Transform) apply(g grid) grid {
+	switch t {
+	case TransformMirror:
+		g = g.padded()
+		out := make(grid, len(g))
+		for r, row := range g {
+			for c := len(row) - 1; c >= 0; c-- {
+				out[r] = append(out[r], swapped(row[c], mirrorSwaps))
+			}
+		}
+		return out
+
+	case TransformFlip:
+		out := make(grid, len(g))
+		for r, row := range g {
+			for _, c := range row {
+				out[len(g)-1-r] = append(out[len(g)-1-r], swapped(c, flipSwaps))
+			}
+		}
+		return out
+
+	case TransformRotate180:
+		return TransformFlip.apply(TransformMirror.apply(g))
+
+	case TransformRotate90, TransformRotate270:
+		g = g.padded()
+		if len(g) == 0 {
+			return g
+		}
+		height, width := len(g), len(g[0])
+		out := make(grid, width)
+		for r := range out {
+			out[r] = make([]cell, height)
+		}
+		for r, row := range g {
+			for c, cl := range row {
+				if t == TransformRotate90 {
+					// the left column becomes the top row
+					out[c][height-1-r] = swapped(cl, rotate90Swaps)
+				} else {
+					// the right column becomes the top row
+					out[width-1-c][r] = swapped(cl, rotate270Swaps)
+				}
+			}
+		}
+		return out
+	}
+	return g
+}
+
+// TransformArt applies transforms to art that has already been rendered,
+// e.g. the result of RenderInput or RenderWithColor; color codes in the
+// art are kept, so each colored part is still colored after the transform
+// (which input character drew a cell isn't known any more, so neighbouring
+// cells of the same color are written as one span)
+func TransformArt(art string, transforms ...Transform) string {
+	g := parseGrid(art)
+	for _, t := range transforms {
+		g = t.apply(g)
+	}
+	for _, row := range g {
+		for c := range row {
+			row[c].owner = 0
+		}
+	}
+	return g.String()
+}
+
+// parseGrid turns rendered text back into cells, reading the color codes
+// every colored span gets its own owner, so written back unchanged it's
+// the same text
+func parseGrid(art string) grid {
+	lines := strings.Split(strings.TrimSuffix(art, "\n"), "\n")
+	if art == "" {
+		return nil
+	}
+
+	g := make(grid, len(lines))
+	span := 0
+	for r, line := range lines {
+		color := ""
+		runes := []rune(line)
+		for i := 0; i < len(runes); i++ {
+			// a color code is ESC [ ... m
+			if runes[i] == '\033' && i+1 < len(runes) && runes[i+1] == '[' {
+				end := i + 2
+				for end < len(runes) && runes[end] != 'm' {
+					end++
+				}
+				// without the m it isn't a color code, just text
+				if end < len(runes) {
+					code := string(runes[i : end+1])
+					if code == ResetColor {
+						color = ""
+					} else {
+						color = code
+						span++
+					}
+					i = end
+					continue
+				}
+			}
+			g[r] = append(g[r], cell{ch: runes[i], color: color, owner: span})
+		}
+	}
+	return g
+}
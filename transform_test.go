@@ -0,0 +1,106 @@
+package main
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+// a small banner where every character is 2 wide and 2 tall
+var transformBanner = Banner{
+	'a': {"/_", "| "},
+	'b': {"(-", "_)"},
+}
+
+// test reading --transform values
+func TestParseTransforms(t *testing.T) {
+	got, err := ParseTransforms("mirror,rotate90")
+	if err != nil || !slices.Equal(got, []Transform{TransformMirror, TransformRotate90}) {
+		t.Errorf("expected mirror and rotate90, got %v (%v)", got, err)
+	}
+	if _, err := ParseTransforms("sideways"); err == nil {
+		t.Error("expected an error for an unknown transform")
+	}
+}
+
+// test each transform on plain output, including the character swaps
+func TestTransforms(t *testing.T) {
+	tests := []struct {
+		transform Transform
+		want      string
+	}{
+		// /_(-     -)_\
+		// | _)  -> (_ |
+		{TransformMirror, "-)_\\\n(_ |\n"},
+		{TransformFlip, "| ‾)\n\\‾(-\n"},
+		{TransformRotate180, "(‾ |\n-)‾/\n"},
+		{TransformRotate90, "-\\\n |\n|(\n)|\n"},
+		{TransformRotate270, "|)\n(|\n| \n\\-\n"},
+	}
+	for _, tt := range tests {
+		opts := RenderOptions{Transforms: []Transform{tt.transform}}
+		if got := RenderInputOptions("ab", transformBanner, opts); got != tt.want {
+			t.Errorf("transform %d: expected %q, got %q", tt.transform, tt.want, got)
+		}
+	}
+}
+
+// test that a colored character stays colored after a transform
+func TestTransformColor(t *testing.T) {
+	opts := RenderOptions{Transforms: []Transform{TransformMirror}}
+	got := RenderWithColorOptions("ab", transformBanner, "\033[31m", []int{0}, opts)
+	want := "-)\033[31m_\\\033[0m\n(_\033[31m |\033[0m\n"
+	if got != want {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+}
+
+// test transforming art that was already rendered with colors
+func TestTransformArt(t *testing.T) {
+	art := RenderWithColor("ab", transformBanner, "\033[31m", []int{0})
+
+	// no transforms gives the same text back
+	if got := TransformArt(art); got != art {
+		t.Errorf("expected %q unchanged, got %q", art, got)
+	}
+
+	for _, tr := range []Transform{TransformMirror, TransformFlip, TransformRotate90, TransformRotate180, TransformRotate270} {
+		opts := RenderOptions{Transforms: []Transform{tr}}
+		want := RenderWithColorOptions("ab", transformBanner, "\033[31m", []int{0}, opts)
+		got := TransformArt(art, tr)
+		// the spans may be split differently, the text must not be
+		if stripColors(got) != stripColors(want) {
+			t.Errorf("transform %d: expected %q, got %q", tr, want, got)
+		}
+		if strings.Count(got, "\033[31m") != strings.Count(got, ResetColor) {
+			t.Errorf("transform %d: unbalanced color codes in %q", tr, got)
+		}
+	}
+}
+
+// test that a color code cut off before its m is kept as text
+// (it used to read past the end of the line)
+func TestParseGridUnterminatedColor(t *testing.T) {
+	for _, line := range []string{"ab\033[", strings.Repeat("x", 62) + "\033[", "a\033[31"} {
+		g := parseGrid(line)
+		if len(g) != 1 || len(g[0]) != len([]rune(line)) {
+			t.Errorf("%q: expected every character as a cell, got %v", line, g)
+			continue
+		}
+		if got := stripColors(line); got != line+"\n" {
+			t.Errorf("%q: expected the text back, got %q", line, got)
+		}
+	}
+}
+
+// stripColors removes the color codes from rendered text
+func stripColors(s string) string {
+	var b strings.Builder
+	for _, row := range parseGrid(s) {
+		for _, c := range row {
+			b.WriteRune(c.ch)
+		}
+		b.WriteRune('\n')
+	}
+	return b.String()
+}
@@ -0,0 +1,184 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// BannerVariant makes a new banner out of an existing one
+// ink is the banner's ink characters ("" means anything but spaces)
+type BannerVariant func(b Banner, ink string) Banner
+
+// bannerVariants are the modifiers that can follow a banner name,
+// as in standard+bold or shadow+hollow+bold (applied left to right)
+var bannerVariants = map[string]BannerVariant{
+	"bold":    BoldBanner,
+	"hollow":  HollowBanner,
+	"outline": HollowBanner,
+	"inverse": InverseBanner,
+}
+
+// splitBannerVariants splits "standard+bold+hollow" into the banner name
+// and its modifiers; only known modifiers are split off, so a file name
+// that happens to contain a + still works
+func splitBannerVariants(name string) (string, []string) {
+	var variants []string
+	for {
+		i := strings.LastIndex(name, "+")
+		if i < 0 || bannerVariants[name[i+1:]] == nil {
+			break
+		}
+		variants = append([]string{name[i+1:]}, variants...)
+		name = name[:i]
+	}
+	return name, variants
+}
+
+// isInk reports whether a character in a glyph is ink rather than background
+func isInk(ch rune, ink string) bool {
+	if ink != "" {
+		return strings.ContainsRune(ink, ch)
+	}
+	return ch != ' ' && ch != hardblank
+}
+
+// glyphGrid turns a glyph into a grid of characters, every row padded with
+// spaces to the glyph's width, so neighbours can be looked up by position
+func glyphGrid(glyph []string) [][]rune {
+	width := glyphWidth(glyph)
+	cells := make([][]rune, len(glyph))
+	for row, line := range glyph {
+		cells[row] = []rune(line + strings.Repeat(" ", width-len([]rune(line))))
+	}
+	return cells
+}
+
+// gridGlyph turns a grid of characters back into glyph rows
+func gridGlyph(cells [][]rune) []string {
+	glyph := make([]string, len(cells))
+	for row := range cells {
+		glyph[row] = string(cells[row])
+	}
+	return glyph
+}
+
+// mapGlyphs applies a change to every glyph of a banner
+func mapGlyphs(b Banner, change func(cells [][]rune) [][]rune) Banner {
+	out := make(Banner, len(b))
+	for ch, glyph := range b {
+		out[ch] = gridGlyph(change(glyphGrid(glyph)))
+	}
+	return out
+}
+
+// BoldBanner thickens the ink: every ink character is also drawn one
+// column to its right, so each glyph gets one column wider
+func BoldBanner(b Banner, ink string) Banner {
+	return mapGlyphs(b, func(cells [][]rune) [][]rune {
+		bold := make([][]rune, len(cells))
+		for row, line := range cells {
+			bold[row] = append(append([]rune{}, line...), ' ')
+			for col, ch := range line {
+				if isInk(ch, ink) && !isInk(bold[row][col+1], ink) {
+					bold[row][col+1] = ch
+				}
+			}
+		}
+		return bold
+	})
+}
+
+// HollowBanner keeps only the edges of the ink: an ink character whose
+// four neighbours (up, down, left, right) are all ink is cleared
+// characters at the edge of the glyph always count as edges
+func HollowBanner(b Banner, ink string) Banner {
+	return mapGlyphs(b, func(cells [][]rune) [][]rune {
+		inkAt := func(row, col int) bool {
+			return row >= 0 && row < len(cells) && col >= 0 && col < len(cells[row]) && isInk(cells[row][col], ink)
+		}
+		hollow := make([][]rune, len(cells))
+		for row, line := range cells {
+			hollow[row] = append([]rune{}, line...)
+			for col := range line {
+				if inkAt(row, col) && inkAt(row-1, col) && inkAt(row+1, col) && inkAt(row, col-1) && inkAt(row, col+1) {
+					hollow[row][col] = ' '
+				}
+			}
+		}
+		return hollow
+	})
+}
+
+// InverseBanner swaps ink and background inside each glyph: ink becomes a
+// space and background is filled with the banner's first ink character
+// (or # if the banner doesn't say which characters are ink)
+func InverseBanner(b Banner, ink string) Banner {
+	fill := defaultInk
+	if ink != "" {
+		fill = []rune(ink)[0]
+	}
+	return mapGlyphs(b, func(cells [][]rune) [][]rune {
+		inverse := make([][]rune, len(cells))
+		for row, line := range cells {
+			inverse[row] = make([]rune, len(line))
+			for col, ch := range line {
+				if isInk(ch, ink) {
+					inverse[row][col] = ' '
+				} else {
+					inverse[row][col] = fill
+				}
+			}
+		}
+		return inverse
+	})
+}
+
+// Scale is how many times bigger to draw each glyph, across and down
+// the zero value (and 1x1) leaves glyphs as they are
+type Scale struct {
+	X, Y int
+}
+
+// maxScale is the most --scale can make glyphs bigger in each direction;
+// the whole banner is scaled up front, so huge scales would run out of memory
+const maxScale = 10
+
+// ParseScale reads a --scale value: "2" for both directions, or "3x1"
+// for 3 times wider and 1 times taller (each from 1 to maxScale)
+func ParseScale(value string) (Scale, error) {
+	xs, ys, found := strings.Cut(value, "x")
+	if !found {
+		ys = xs
+	}
+	x, errX := strconv.Atoi(xs)
+	y, errY := strconv.Atoi(ys)
+	if errX != nil || errY != nil || x < 1 || y < 1 || x > maxScale || y > maxScale {
+		return Scale{}, fmt.Errorf("invalid scale %q (expected a number from 1 to %d like 2, or WxH like 3x1)", value, maxScale)
+	}
+	return Scale{X: x, Y: y}, nil
+}
+
+// ScaleBanner repeats every character of every glyph s.X times across and
+// every row s.Y times down, so the banner's height grows with it
+func ScaleBanner(b Banner, s Scale) Banner {
+	x, y := max(s.X, 1), max(s.Y, 1)
+	if x == 1 && y == 1 {
+		return b
+	}
+	out := make(Banner, len(b))
+	for ch, glyph := range b {
+		rows := make([]string, 0, len(glyph)*y)
+		for _, line := range glyph {
+			var wide strings.Builder
+			for _, c := range line {
+				wide.WriteString(strings.Repeat(string(c), x))
+			}
+			for i := 0; i < y; i++ {
+				rows = append(rows, wide.String())
+			}
+		}
+		out[ch] = rows
+	}
+	return out
+}
@@ -0,0 +1,126 @@
+package main
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+// test that bold draws each ink character one column further right
+func TestBoldBanner(t *testing.T) {
+	b := Banner{'I': {"|  ", " / "}}
+	got := BoldBanner(b, "")['I']
+	want := []string{"||  ", " // "}
+	if !slices.Equal(got, want) {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+}
+
+// test that hollow clears ink surrounded by ink, and keeps the edges
+func TestHollowBanner(t *testing.T) {
+	b := Banner{'O': {"###", "###", "###"}}
+	got := HollowBanner(b, "#")['O']
+	want := []string{"###", "# #", "###"}
+	if !slices.Equal(got, want) {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+}
+
+// test that inverse swaps ink and background using the banner's ink
+func TestInverseBanner(t *testing.T) {
+	b := Banner{'T': {"*** ", " *"}}
+	got := InverseBanner(b, "*")['T']
+	want := []string{"   *", "* **"}
+	if !slices.Equal(got, want) {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+}
+
+// test splitting modifiers off a banner name
+func TestSplitBannerVariants(t *testing.T) {
+	tests := []struct {
+		name     string
+		base     string
+		variants []string
+	}{
+		{"standard", "standard", nil},
+		{"standard+bold", "standard", []string{"bold"}},
+		{"shadow+hollow+bold", "shadow", []string{"hollow", "bold"}},
+		{"c++", "c++", nil},
+		{"my+font.txt", "my+font.txt", nil},
+	}
+	for _, tt := range tests {
+		base, variants := splitBannerVariants(tt.name)
+		if base != tt.base || !slices.Equal(variants, tt.variants) {
+			t.Errorf("%q: expected %q %q, got %q %q", tt.name, tt.base, tt.variants, base, variants)
+		}
+	}
+}
+
+// test that a banner+modifier name loads and renders like any banner
+func TestRegistryLoadVariant(t *testing.T) {
+	registry := NewBannerRegistry(nil)
+	if !registry.Has("standard+bold") {
+		t.Fatal("expected standard+bold to be found")
+	}
+	banner, info, err := registry.LoadInfo("standard+bold")
+	if err != nil {
+		t.Fatalf("failed to load standard+bold: %v", err)
+	}
+	if info.Name != "standard+bold" {
+		t.Errorf("expected name standard+bold, got %q", info.Name)
+	}
+	if !strings.Contains(RenderInput("I", banner), "||") {
+		t.Errorf("expected thicker lines, got:\n%s", RenderInput("I", banner))
+	}
+	if registry.Has("standard+nope") {
+		t.Error("unknown modifiers should not be found")
+	}
+}
+
+// test reading --scale values
+func TestParseScale(t *testing.T) {
+	tests := []struct {
+		value string
+		want  Scale
+	}{
+		{"2", Scale{2, 2}},
+		{"3x1", Scale{3, 1}},
+		{"1x4", Scale{1, 4}},
+	}
+	for _, tt := range tests {
+		got, err := ParseScale(tt.value)
+		if err != nil || got != tt.want {
+			t.Errorf("%q: expected %v, got %v (%v)", tt.value, tt.want, got, err)
+		}
+	}
+	for _, bad := range []string{"", "0", "x2", "2x", "-1", "big", "11", "1000", "2x11"} {
+		if _, err := ParseScale(bad); err == nil {
+			t.Errorf("%q: expected an error", bad)
+		}
+	}
+}
+
+// test that scaling repeats characters across and rows down
+func TestScaleBanner(t *testing.T) {
+	b := Banner{'L': {"| ", "|_"}}
+	got := ScaleBanner(b, Scale{X: 2, Y: 2})
+	want := []string{"||  ", "||  ", "||__", "||__"}
+	if !slices.Equal(got['L'], want) {
+		t.Errorf("expected %q, got %q", want, got['L'])
+	}
+	if got.Height() != 4 {
+		t.Errorf("expected height 4, got %d", got.Height())
+	}
+}
+
+// test that colored output stays lined up when scaled
+func TestRenderScaledColor(t *testing.T) {
+	b := Banner{'a': {"a", "a"}, 'b': {"b", "b"}}
+	opts := RenderOptions{Scale: Scale{X: 2, Y: 2}}
+	got := RenderWithColorOptions("ab", b, "\033[31m", []int{1}, opts)
+	row := "aa\033[31mbb\033[0m\n"
+	if want := strings.Repeat(row, 4); got != want {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+}
@@ -0,0 +1,140 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Direction is which way characters follow each other
+type Direction int
+
+const (
+	DirectionHorizontal Direction = iota // left to right (the default)
+	DirectionVertical                    // top to bottom, lines side by side
+	DirectionRTL                         // every character right to left, lines right-aligned
+	DirectionAuto                        // bidi order, each line by its first letter
+)
+
+// directionNames are the names used by --direction
+var directionNames = map[string]Direction{
+	"horizontal": DirectionHorizontal,
+	"vertical":   DirectionVertical,
+	"rtl":        DirectionRTL,
+	"auto":       DirectionAuto,
+}
+
+// ParseDirection reads a --direction value
+func ParseDirection(value string) (Direction, error) {
+	direction, ok := directionNames[value]
+	if !ok {
+		return DirectionHorizontal, fmt.Errorf("unknown direction %q (expected horizontal, vertical, rtl or auto)", value)
+	}
+	return direction, nil
+}
+
+// lineIsRTL reports whether a line of text is drawn right to left
+func (d Direction) lineIsRTL(chars []rune) bool {
+	switch d {
+	case DirectionRTL:
+		return true
+	case DirectionAuto:
+		return startsRTL(chars)
+	}
+	return false
+}
+
+// usesBidi reports whether lines may be drawn right to left
+func (d Direction) usesBidi() bool {
+	return d == DirectionRTL || d == DirectionAuto
+}
+
+// verticalColumnGap is the number of spaces between the columns of
+// vertical text (one column per line of input)
+const verticalColumnGap = 2
+
+// maxSpacing is the most blank rows --spacing can put between characters,
+// as each one adds a row of blank cells for every character
+const maxSpacing = 20
+
+// renderVerticalGrid stacks the characters of each line top to bottom, with
+// opts.Spacing blank rows between them, and puts the lines side by side
+// each character is placed in its column by opts.Align; indexes count
+// characters across all lines (plus one for each newline), as usual
+func renderVerticalGrid(input string, banner Banner, colorCode string, indexes []int, opts RenderOptions) grid {
+	height := banner.Height()
+	empty := make([]string, height)
+
+	lines := strings.Split(input, "\n")
+	// a newline at the very end doesn't start another column
+	if len(lines) > 1 && lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+
+	var columns []grid
+	pos := 0
+	for _, line := range lines {
+		chars := []rune(line)
+
+		// the column is as wide as its widest character
+		width := 0
+		for _, ch := range chars {
+			glyph, _ := banner.Glyph(ch)
+			width = max(width, glyphWidth(glyph))
+		}
+
+		var column grid
+		for i, ch := range chars {
+			if i > 0 {
+				for s := 0; s < opts.Spacing; s++ {
+					column = append(column, blankCells(width))
+				}
+			}
+
+			glyph, ok := banner.Glyph(ch)
+			if !ok {
+				// character not in banner, use empty space
+				glyph = empty
+			}
+			color := ""
+			if colorCode != "" && ContainsIndex(indexes, pos) {
+				color = colorCode
+			}
+			for _, row := range glyphCells(glyph, height, color, pos) {
+				column = append(column, alignCells(row, width, opts.Align))
+			}
+			pos++
+		}
+		pos++ // the newline
+		columns = append(columns, column)
+	}
+
+	return joinColumns(columns, verticalColumnGap)
+}
+
+// joinColumns puts grids side by side, gap spaces apart, top aligned
+func joinColumns(columns []grid, gap int) grid {
+	height := 0
+	for _, column := range columns {
+		height = max(height, len(column))
+	}
+
+	out := make(grid, height)
+	for i, column := range columns {
+		column = column.padded()
+		width := 0
+		if len(column) > 0 {
+			width = len(column[0])
+		}
+		for r := range out {
+			if i > 0 {
+				out[r] = append(out[r], blankCells(gap)...)
+			}
+			if r < len(column) {
+				out[r] = append(out[r], column[r]...)
+			} else {
+				out[r] = append(out[r], blankCells(width)...)
+			}
+		}
+	}
+	return out
+}
@@ -0,0 +1,87 @@
+package main
+
+import "testing"
+
+// a small banner with characters of different widths
+var verticalBanner = Banner{
+	'i': {"i", "i"},
+	'm': {"mmm", "mmm"},
+}
+
+// test stacking characters, with spacing and alignment
+func TestRenderVertical(t *testing.T) {
+	tests := []struct {
+		name string
+		opts RenderOptions
+		want string
+	}{
+		{"left", RenderOptions{Direction: DirectionVertical}, "i  \ni  \nmmm\nmmm\n"},
+		{"center", RenderOptions{Direction: DirectionVertical, Align: AlignCenter}, " i \n i \nmmm\nmmm\n"},
+		{"right", RenderOptions{Direction: DirectionVertical, Align: AlignRight}, "  i\n  i\nmmm\nmmm\n"},
+		{"spacing", RenderOptions{Direction: DirectionVertical, Spacing: 1}, "i  \ni  \n   \nmmm\nmmm\n"},
+		// a column has no words to spread out, justify is left
+		{"justify", RenderOptions{Direction: DirectionVertical, Align: AlignJustify}, "i  \ni  \nmmm\nmmm\n"},
+	}
+	for _, tt := range tests {
+		if got := RenderInputOptions("im", verticalBanner, tt.opts); got != tt.want {
+			t.Errorf("%s: expected %q, got %q", tt.name, tt.want, got)
+		}
+	}
+}
+
+// test that lines of input become columns, and colors follow the characters
+func TestRenderVerticalColumns(t *testing.T) {
+	opts := RenderOptions{Direction: DirectionVertical}
+
+	got := RenderInputOptions("m\\nii", verticalBanner, opts)
+	want := "mmm  i\nmmm  i\n     i\n     i\n"
+	if got != want {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+
+	// position 3 is the second i (m, newline, i, i)
+	got = RenderWithColorOptions("m\\nii", verticalBanner, "\033[31m", []int{3}, opts)
+	want = "mmm  i\nmmm  i\n     \033[31mi\033[0m\n     \033[31mi\033[0m\n"
+	if got != want {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+}
+
+// test reading --direction and --align values
+func TestParseDirectionAndAlign(t *testing.T) {
+	if d, err := ParseDirection("vertical"); err != nil || d != DirectionVertical {
+		t.Errorf("expected vertical, got %v (%v)", d, err)
+	}
+	if _, err := ParseDirection("diagonal"); err == nil {
+		t.Error("expected an error for an unknown direction")
+	}
+	if a, err := ParseAlign("center"); err != nil || a != AlignCenter {
+		t.Errorf("expected center, got %v (%v)", a, err)
+	}
+	if _, err := ParseAlign("middle"); err == nil {
+		t.Error("expected an error for an unknown alignment")
+	}
+}
+
+// test that --align=justify is refused for vertical text
+func TestParseColorArgs_VerticalJustify(t *testing.T) {
+	if _, err := ParseColorArgs([]string{"program", "--direction=vertical", "--align=justify", "Hi"}); err == nil {
+		t.Error("expected an error for justify in vertical text")
+	}
+	if _, err := ParseColorArgs([]string{"program", "--direction=vertical", "--align=center", "Hi"}); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+// test that --spacing has to be a number of rows that isn't too big
+func TestParseColorArgs_Spacing(t *testing.T) {
+	opts, err := ParseColorArgs([]string{"program", "--direction=vertical", "--spacing=2", "Hi"})
+	if err != nil || opts.Spacing != 2 {
+		t.Errorf("expected spacing 2, got %d (%v)", opts.Spacing, err)
+	}
+	for _, bad := range []string{"-1", "x", "21", "1000000000"} {
+		if _, err := ParseColorArgs([]string{"program", "--spacing=" + bad, "Hi"}); err == nil {
+			t.Errorf("%q: expected an error", bad)
+		}
+	}
+}
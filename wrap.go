@@ -0,0 +1,141 @@
+package main
+
+import (
+	"os"
+	"strconv"
+)
+
+// renderedWidth is how many columns a piece of text takes once rendered
+// (with the layout and effects, but no color)
+func renderedWidth(chars []rune, banner Banner, opts RenderOptions) int {
+	width := 0
+	for _, row := range opts.distort(renderLineGrid(chars, banner, "", nil, opts)) {
+		width = max(width, len(row))
+	}
+	return width
+}
+
+// lineMeasure works out how wide a piece of a line renders while it grows
+// a character at a time: each glyph is joined on the way renderLineGrid
+// joins them, so the piece isn't rendered again for every character
+type lineMeasure struct {
+	banner    Banner
+	opts      RenderOptions
+	rows      grid
+	prevWidth int
+}
+
+// newLineMeasure starts measuring an empty piece
+func newLineMeasure(banner Banner, opts RenderOptions) *lineMeasure {
+	return &lineMeasure{banner: banner, opts: opts, rows: make(grid, banner.Height())}
+}
+
+// add joins ch on the end of the piece and returns the piece's width
+// (with the layout and effects, like renderedWidth)
+func (m *lineMeasure) add(ch rune) int {
+	height := len(m.rows)
+	glyph, ok := m.banner.Glyph(ch)
+	if !ok {
+		// character not in banner, use empty space
+		glyph = make([]string, height)
+	}
+	width := glyphWidth(glyph)
+	m.opts.Layout.addGlyph(m.rows, glyphCells(glyph, height, "", 0), m.prevWidth, width)
+	m.prevWidth = width
+	return m.opts.distortedWidth(gridWidth(m.rows), height)
+}
+
+// wrapLine splits one line of text into pieces that render no wider than
+// opts.Width columns, breaking between words where it can and inside a
+// word only when the word alone is too wide; it returns the start and end
+// position of every piece in chars, so colors can still be matched up
+// spaces where a line is broken are left out
+func wrapLine(chars []rune, banner Banner, opts RenderOptions) [][2]int {
+	var pieces [][2]int
+	start := 0
+	for start < len(chars) {
+		// a wrapped line doesn't start with the spaces it was broken at
+		if len(pieces) > 0 {
+			for start < len(chars) && chars[start] == ' ' {
+				start++
+			}
+			if start == len(chars) {
+				break
+			}
+		}
+
+		// the furthest the characters fit, then back to the last place
+		// the words so far could be broken at
+		end := fitEnd(chars, start, banner, opts)
+		if end < len(chars) {
+			breakAt := -1
+			for i := start + 1; i <= end; i++ {
+				if chars[i] == ' ' && chars[i-1] != ' ' {
+					breakAt = i
+				}
+			}
+			if breakAt >= 0 {
+				// the longest run of whole words that fits
+				end = breakAt
+			} else {
+				// the first word is too wide by itself: as many characters
+				// as fit, but at least one so we always get somewhere
+				end = max(end, start+1)
+			}
+		}
+
+		pieces = append(pieces, [2]int{start, end})
+		start = end
+	}
+	return pieces
+}
+
+// fitEnd returns the furthest end for which chars[start:end] renders no
+// wider than opts.Width (start if not even one character fits)
+func fitEnd(chars []rune, start int, banner Banner, opts RenderOptions) int {
+	if opts.Direction.usesBidi() {
+		// right-to-left text is drawn in another order, so a piece can't
+		// be measured by adding characters to its end: render pieces twice
+		// as long until one doesn't fit, then halve the difference
+		fits := func(end int) bool {
+			return renderedWidth(chars[start:end], banner, opts) <= opts.Width
+		}
+		end, step := start, 1
+		for end+step <= len(chars) && fits(end+step) {
+			end += step
+			step *= 2
+		}
+		tooFar := min(end+step, len(chars)+1)
+		for tooFar-end > 1 {
+			mid := (end + tooFar) / 2
+			if fits(mid) {
+				end = mid
+			} else {
+				tooFar = mid
+			}
+		}
+		return end
+	}
+
+	measure := newLineMeasure(banner, opts)
+	end := start
+	for end < len(chars) && measure.add(chars[end]) <= opts.Width {
+		end++
+	}
+	return end
+}
+
+// isTerminal reports whether f is a terminal (not a file or a pipe)
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// terminalWidth returns how many columns wide the terminal f is, or 0 if
+// that can't be found out; $COLUMNS wins if it's set
+func terminalWidth(f *os.File) int {
+	if columns, err := strconv.Atoi(os.Getenv("COLUMNS")); err == nil && columns > 0 {
+		return columns
+	}
+	return ioctlWidth(f)
+}
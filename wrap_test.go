@@ -0,0 +1,97 @@
+package main
+
+import (
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+// one-column glyphs, so widths are easy to count
+var wrapBanner = Banner{
+	' ': {" "}, 'a': {"a"}, 'b': {"b"}, 'c': {"c"}, 'd': {"d"},
+}
+
+// test where lines are broken
+func TestWrapLine(t *testing.T) {
+	tests := []struct {
+		text  string
+		width int
+		want  [][2]int
+	}{
+		// fits as it is
+		{"ab cd", 5, [][2]int{{0, 5}}},
+		// broken between the words, the space is left out
+		{"ab cd", 4, [][2]int{{0, 2}, {3, 5}}},
+		// as many words as fit on each line
+		{"a b c d", 3, [][2]int{{0, 3}, {4, 7}}},
+		// a word too wide by itself is broken inside
+		{"abcd", 3, [][2]int{{0, 3}, {3, 4}}},
+		{"a bcdd", 2, [][2]int{{0, 1}, {2, 4}, {4, 6}}},
+	}
+	for _, tt := range tests {
+		got := wrapLine([]rune(tt.text), wrapBanner, RenderOptions{Width: tt.width})
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("%q at width %d: expected %v, got %v", tt.text, tt.width, tt.want, got)
+		}
+	}
+}
+
+// test that every wrapped line of real banner output fits the width
+func TestRenderWrapFits(t *testing.T) {
+	banner, err := LoadBanner("banners/shadow.txt")
+	if err != nil {
+		t.Fatalf("failed to load banner: %v", err)
+	}
+	out := RenderInputOptions("Hello there world", banner, RenderOptions{Width: 40})
+	lines := strings.Split(strings.TrimSuffix(out, "\n"), "\n")
+	if len(lines) <= banner.Height() {
+		t.Errorf("expected the text to be wrapped, got:\n%s", out)
+	}
+	for _, line := range lines {
+		if len(line) > 40 {
+			t.Errorf("line wider than 40 columns: %q", line)
+		}
+	}
+}
+
+// test that colors stay on the right characters after wrapping
+func TestRenderWrapColor(t *testing.T) {
+	// color "bc" (positions 1 and 3), which is broken over two lines
+	got := RenderWithColorOptions("ab cd", wrapBanner, "\033[31m", []int{1, 3}, RenderOptions{Width: 2})
+	want := "a\033[31mb\033[0m\n\033[31mc\033[0md\n"
+	if got != want {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+}
+
+// test that a very long line is wrapped quickly (each piece is measured
+// as it grows, not rendered again for every character), and that every
+// piece fits, with the layouts and right-to-left text too
+func TestWrapLongLine(t *testing.T) {
+	banner, err := LoadBanner("banners/standard.txt")
+	if err != nil {
+		t.Fatalf("failed to load banner: %v", err)
+	}
+	line := []rune(strings.Repeat("hello ", 2000))
+	smush, _ := ParseLayout("smush")
+
+	for _, opts := range []RenderOptions{
+		{Width: 80},
+		{Width: 80, Layout: smush, Shear: 1},
+		{Width: 80, Direction: DirectionRTL},
+	} {
+		began := time.Now()
+		pieces := wrapLine(line, banner, opts)
+		if took := time.Since(began); took > 5*time.Second {
+			t.Errorf("%+v: wrapping took %v", opts, took)
+		}
+
+		for _, piece := range pieces {
+			if width := renderedWidth(line[piece[0]:piece[1]], banner, opts); width > opts.Width {
+				t.Errorf("%+v: piece %v is %d columns wide", opts, piece, width)
+				break
+			}
+		}
+	}
+}